@@ -0,0 +1,96 @@
+package bytes
+
+import (
+	"fmt"
+)
+
+// base58Alphabet is the Bitcoin base58 alphabet.
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58Indexes maps the characters of base58Alphabet to their indexes, -1 for invalid characters.
+var base58Indexes [256]int8
+
+func init() {
+	for i := range base58Indexes {
+		base58Indexes[i] = -1
+	}
+	for i := 0; i < len(base58Alphabet); i++ {
+		base58Indexes[base58Alphabet[i]] = int8(i)
+	}
+}
+
+// base58Encoding implements textEncoding with the Bitcoin base58 alphabet, leading zero bytes
+// are encoded as leading '1's.
+var base58Encoding textEncoding = base58Codec{}
+
+type base58Codec struct{}
+
+func (base58Codec) EncodeToString(src []byte) string {
+	zeros := 0
+	for zeros < len(src) && src[zeros] == 0 {
+		zeros++
+	}
+
+	// log(256) / log(58) ≈ 1.37, rounded up.
+	size := (len(src)-zeros)*138/100 + 1
+	buf := make([]byte, size)
+	high := size - 1
+	for _, b := range src[zeros:] {
+		carry := int(b)
+		j := size - 1
+		for ; j > high || carry != 0; j-- {
+			carry += 256 * int(buf[j])
+			buf[j] = byte(carry % 58)
+			carry /= 58
+		}
+		high = j
+	}
+
+	start := 0
+	for start < size && buf[start] == 0 {
+		start++
+	}
+
+	dst := make([]byte, zeros+size-start)
+	for i := 0; i < zeros; i++ {
+		dst[i] = base58Alphabet[0]
+	}
+	for i, d := range buf[start:] {
+		dst[zeros+i] = base58Alphabet[d]
+	}
+	return string(dst)
+}
+
+func (base58Codec) DecodeString(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+
+	// log(58) / log(256) ≈ 0.733, rounded up.
+	size := (len(s)-zeros)*733/1000 + 1
+	buf := make([]byte, size)
+	high := size - 1
+	for i := zeros; i < len(s); i++ {
+		carry := int(base58Indexes[s[i]])
+		if carry < 0 {
+			return nil, fmt.Errorf("illegal base58 data at input byte %d", i)
+		}
+		j := size - 1
+		for ; j > high || carry != 0; j-- {
+			carry += 58 * int(buf[j])
+			buf[j] = byte(carry)
+			carry >>= 8
+		}
+		high = j
+	}
+
+	start := 0
+	for start < size && buf[start] == 0 {
+		start++
+	}
+
+	dst := make([]byte, zeros+size-start)
+	copy(dst[zeros:], buf[start:])
+	return dst, nil
+}
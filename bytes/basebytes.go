@@ -0,0 +1,267 @@
+package bytes
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// textEncoding is the common interface of the encodings used by the byte types.
+type textEncoding interface {
+	EncodeToString(src []byte) string
+	DecodeString(s string) ([]byte, error)
+}
+
+var (
+	base64Encoding textEncoding = base64.RawURLEncoding
+	base32Encoding textEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+// marshalJSONWith encodes `bz` with `enc` as a JSON quoted string.
+func marshalJSONWith(enc textEncoding, bz []byte) ([]byte, error) {
+	s := enc.EncodeToString(bz)
+	buf := make([]byte, 0, len(s)+2) // +2 for quotation marks
+	buf = append(buf, '"')
+	buf = append(buf, s...)
+	buf = append(buf, '"')
+	return buf, nil
+}
+
+// unmarshalJSONWith decodes a JSON quoted string with `enc`.
+func unmarshalJSONWith(enc textEncoding, name string, data []byte) ([]byte, error) {
+	if len(data) < 2 || data[0] != '"' || data[len(data)-1] != '"' {
+		return nil, fmt.Errorf("invalid %s string: %s", name, data)
+	}
+
+	return enc.DecodeString(string(data[1 : len(data)-1]))
+}
+
+// formatWith writes either address of 0th element in a slice in base 16 notation, with leading 0x
+// (%p), or `bz` encoded with `enc` to s.
+func formatWith(enc textEncoding, bz []byte, s fmt.State, verb rune) {
+	switch verb {
+	case 'p':
+		s.Write([]byte(fmt.Sprintf("%p", bz)))
+	default:
+		s.Write([]byte(enc.EncodeToString(bz)))
+	}
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// Base64Bytes enables unpadded base64url-encoding for json/encoding.
+type Base64Bytes []byte
+
+var (
+	_ json.Marshaler           = Base64Bytes{}
+	_ json.Unmarshaler         = &Base64Bytes{}
+	_ encoding.TextMarshaler   = Base64Bytes{}
+	_ encoding.TextUnmarshaler = &Base64Bytes{}
+)
+
+// Marshal returns the raw bytes, the unpadded base64url encoding only applies to JSON and text.
+func (bz Base64Bytes) Marshal() ([]byte, error) {
+	return bz, nil
+}
+
+// Unmarshal sets the Base64Bytes to the raw bytes `data` without decoding.
+func (bz *Base64Bytes) Unmarshal(data []byte) error {
+	*bz = data
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface. The encoding is a JSON quoted string of
+// unpadded base64url.
+func (bz Base64Bytes) MarshalJSON() ([]byte, error) {
+	return marshalJSONWith(base64Encoding, bz)
+}
+
+// UnmarshalJSON implements the json.Umarshaler interface.
+func (bz *Base64Bytes) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	bz2, err := unmarshalJSONWith(base64Encoding, "base64", data)
+	if err != nil {
+		return err
+	}
+	*bz = bz2
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (bz Base64Bytes) MarshalText() ([]byte, error) {
+	return []byte(bz.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (bz *Base64Bytes) UnmarshalText(text []byte) error {
+	bz2, err := base64Encoding.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+	*bz = bz2
+	return nil
+}
+
+// Bytes returns the raw bytes of the Base64Bytes.
+func (bz Base64Bytes) Bytes() []byte {
+	return bz
+}
+
+func (bz Base64Bytes) String() string {
+	return base64Encoding.EncodeToString(bz)
+}
+
+// Format writes either address of 0th element in a slice in base 16 notation,
+// with leading 0x (%p), or the unpadded base64url encoding of the bytes to s.
+func (bz Base64Bytes) Format(s fmt.State, verb rune) {
+	formatWith(base64Encoding, bz, s, verb)
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// Base58Bytes enables base58-encoding with the Bitcoin alphabet for json/encoding.
+type Base58Bytes []byte
+
+var (
+	_ json.Marshaler           = Base58Bytes{}
+	_ json.Unmarshaler         = &Base58Bytes{}
+	_ encoding.TextMarshaler   = Base58Bytes{}
+	_ encoding.TextUnmarshaler = &Base58Bytes{}
+)
+
+// Marshal returns the raw bytes, the base58 encoding only applies to JSON and text.
+func (bz Base58Bytes) Marshal() ([]byte, error) {
+	return bz, nil
+}
+
+// Unmarshal sets the Base58Bytes to the raw bytes `data` without decoding.
+func (bz *Base58Bytes) Unmarshal(data []byte) error {
+	*bz = data
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface. The encoding is a JSON quoted string of
+// base58.
+func (bz Base58Bytes) MarshalJSON() ([]byte, error) {
+	return marshalJSONWith(base58Encoding, bz)
+}
+
+// UnmarshalJSON implements the json.Umarshaler interface.
+func (bz *Base58Bytes) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	bz2, err := unmarshalJSONWith(base58Encoding, "base58", data)
+	if err != nil {
+		return err
+	}
+	*bz = bz2
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (bz Base58Bytes) MarshalText() ([]byte, error) {
+	return []byte(bz.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (bz *Base58Bytes) UnmarshalText(text []byte) error {
+	bz2, err := base58Encoding.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+	*bz = bz2
+	return nil
+}
+
+// Bytes returns the raw bytes of the Base58Bytes.
+func (bz Base58Bytes) Bytes() []byte {
+	return bz
+}
+
+func (bz Base58Bytes) String() string {
+	return base58Encoding.EncodeToString(bz)
+}
+
+// Format writes either address of 0th element in a slice in base 16 notation,
+// with leading 0x (%p), or the base58 encoding of the bytes to s.
+func (bz Base58Bytes) Format(s fmt.State, verb rune) {
+	formatWith(base58Encoding, bz, s, verb)
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// Base32Bytes enables unpadded base32-encoding with the standard alphabet for json/encoding.
+type Base32Bytes []byte
+
+var (
+	_ json.Marshaler           = Base32Bytes{}
+	_ json.Unmarshaler         = &Base32Bytes{}
+	_ encoding.TextMarshaler   = Base32Bytes{}
+	_ encoding.TextUnmarshaler = &Base32Bytes{}
+)
+
+// Marshal returns the raw bytes, the base32 encoding only applies to JSON and text.
+func (bz Base32Bytes) Marshal() ([]byte, error) {
+	return bz, nil
+}
+
+// Unmarshal sets the Base32Bytes to the raw bytes `data` without decoding.
+func (bz *Base32Bytes) Unmarshal(data []byte) error {
+	*bz = data
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface. The encoding is a JSON quoted string of
+// unpadded base32.
+func (bz Base32Bytes) MarshalJSON() ([]byte, error) {
+	return marshalJSONWith(base32Encoding, bz)
+}
+
+// UnmarshalJSON implements the json.Umarshaler interface.
+func (bz *Base32Bytes) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	bz2, err := unmarshalJSONWith(base32Encoding, "base32", data)
+	if err != nil {
+		return err
+	}
+	*bz = bz2
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (bz Base32Bytes) MarshalText() ([]byte, error) {
+	return []byte(bz.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (bz *Base32Bytes) UnmarshalText(text []byte) error {
+	bz2, err := base32Encoding.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+	*bz = bz2
+	return nil
+}
+
+// Bytes returns the raw bytes of the Base32Bytes.
+func (bz Base32Bytes) Bytes() []byte {
+	return bz
+}
+
+func (bz Base32Bytes) String() string {
+	return base32Encoding.EncodeToString(bz)
+}
+
+// Format writes either address of 0th element in a slice in base 16 notation,
+// with leading 0x (%p), or the unpadded base32 encoding of the bytes to s.
+func (bz Base32Bytes) Format(s fmt.State, verb rune) {
+	formatWith(base32Encoding, bz, s, verb)
+}
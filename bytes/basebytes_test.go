@@ -0,0 +1,106 @@
+package bytes_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/daotl/guts/bytes"
+	"github.com/daotl/guts/rand"
+)
+
+func TestBaseBytesJSONMarshal(t *testing.T) {
+	type TestStruct struct {
+		B64 Base64Bytes
+		B58 Base58Bytes
+		B32 Base32Bytes
+	}
+
+	cases := []struct {
+		input    []byte
+		expected string
+	}{
+		{[]byte(``), `{"B64":"","B58":"","B32":""}`},
+		{[]byte(`a`), `{"B64":"YQ","B58":"2g","B32":"ME"}`},
+		{[]byte("\xfb\xff"), `{"B64":"-_8","B58":"LBG","B32":"7P7Q"}`},
+		{[]byte("Hello World!"), `{"B64":"SGVsbG8gV29ybGQh","B58":"2NEpo7TZRRrLZSi2U","B32":"JBSWY3DPEBLW64TMMQQQ"}`},
+		{[]byte("\x00\x00\x28\x7f\xb4\xcd"), `{"B64":"AAAof7TN","B58":"11233QC4","B32":"AAACQ75UZU"}`},
+	}
+
+	for i, tc := range cases {
+		tc := tc
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			ts := TestStruct{B64: tc.input, B58: tc.input, B32: tc.input}
+
+			jsonBytes, err := json.Marshal(ts)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(jsonBytes))
+
+			ts2 := TestStruct{}
+			require.NoError(t, json.Unmarshal(jsonBytes, &ts2))
+			assert.Equal(t, Base64Bytes(tc.input), ts2.B64)
+			assert.Equal(t, Base58Bytes(tc.input), ts2.B58)
+			assert.Equal(t, Base32Bytes(tc.input), ts2.B32)
+		})
+	}
+}
+
+func TestBaseBytesUnmarshalErrors(t *testing.T) {
+	assr := assert.New(t)
+
+	var b64 Base64Bytes
+	assr.Error(json.Unmarshal([]byte(`"SGVsbG8=="`), &b64))
+	assr.Error(json.Unmarshal([]byte(`1`), &b64))
+	var b58 Base58Bytes
+	assr.Error(json.Unmarshal([]byte(`"0OIl"`), &b58))
+	var b32 Base32Bytes
+	assr.Error(json.Unmarshal([]byte(`"me"`), &b32))
+
+	// null leaves the value untouched.
+	b32 = Base32Bytes{0x01}
+	assr.NoError(json.Unmarshal([]byte(`null`), &b32))
+	assr.Equal(Base32Bytes{0x01}, b32)
+}
+
+func TestBaseBytesText(t *testing.T) {
+	req := require.New(t)
+
+	for i := 0; i < 100; i++ {
+		bz := rand.Bytes(i)
+
+		text, err := Base58Bytes(bz).MarshalText()
+		req.NoError(err)
+		var b58 Base58Bytes
+		req.NoError(b58.UnmarshalText(text))
+		req.Equal(bz, b58.Bytes())
+
+		text, err = Base64Bytes(bz).MarshalText()
+		req.NoError(err)
+		var b64 Base64Bytes
+		req.NoError(b64.UnmarshalText(text))
+		req.Equal(bz, b64.Bytes())
+
+		text, err = Base32Bytes(bz).MarshalText()
+		req.NoError(err)
+		var b32 Base32Bytes
+		req.NoError(b32.UnmarshalText(text))
+		req.Equal(bz, b32.Bytes())
+	}
+
+	// TextMarshaler makes the types usable as JSON map values in string form.
+	jsonBytes, err := json.Marshal(map[string]Base58Bytes{"addr": Base58Bytes("Hello World!")})
+	req.NoError(err)
+	req.Equal(`{"addr":"2NEpo7TZRRrLZSi2U"}`, string(jsonBytes))
+}
+
+func TestBaseBytesFormat(t *testing.T) {
+	assr := assert.New(t)
+
+	bz := []byte("Hello World!")
+	assr.Equal("SGVsbG8gV29ybGQh", fmt.Sprintf("%v", Base64Bytes(bz)))
+	assr.Equal("2NEpo7TZRRrLZSi2U", fmt.Sprintf("%s", Base58Bytes(bz)))
+	assr.Equal("JBSWY3DPEBLW64TMMQQQ", Base32Bytes(bz).String())
+}
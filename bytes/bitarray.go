@@ -0,0 +1,241 @@
+package bytes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/daotl/guts/rand"
+)
+
+// BitArray is a compact fixed-size array of bits backed by []byte, bit i is stored the same as
+// GetBit and SetBit do, that is, in the (i%8)-th most significant bit of the (i/8)-th byte.
+//
+// It's encoded in JSON as a string of 'x' for set bits and '_' for unset bits, e.g. "x_x__x".
+//
+// BitArray is not safe for concurrent use.
+type BitArray struct {
+	bits  int
+	elems []byte
+}
+
+var (
+	_ json.Marshaler   = BitArray{}
+	_ json.Unmarshaler = &BitArray{}
+)
+
+// NewBitArray creates a BitArray of `bits` unset bits, panics if `bits` is negative.
+func NewBitArray(bits int) *BitArray {
+	if bits < 0 {
+		panic("bytes: negative BitArray size")
+	}
+	return &BitArray{
+		bits:  bits,
+		elems: make([]byte, (bits+7)/8),
+	}
+}
+
+// Size returns the number of bits in the BitArray, 0 if it's nil.
+func (ba *BitArray) Size() int {
+	if ba == nil {
+		return 0
+	}
+	return ba.bits
+}
+
+// Test reports whether bit `i` is set, returns false if `i` is out of range.
+func (ba *BitArray) Test(i int) bool {
+	if i < 0 || i >= ba.Size() {
+		return false
+	}
+	return GetBit(ba.elems, i)
+}
+
+// Set sets bit `i`, returns false if `i` is out of range.
+func (ba *BitArray) Set(i int) bool {
+	if i < 0 || i >= ba.Size() {
+		return false
+	}
+	SetBit(ba.elems, i, true)
+	return true
+}
+
+// Clear clears bit `i`, returns false if `i` is out of range.
+func (ba *BitArray) Clear(i int) bool {
+	if i < 0 || i >= ba.Size() {
+		return false
+	}
+	SetBit(ba.elems, i, false)
+	return true
+}
+
+// Count returns the number of set bits.
+func (ba *BitArray) Count() int {
+	if ba == nil {
+		return 0
+	}
+	return PopCount(ba.elems)
+}
+
+// NextSet returns the index of the first set bit at or after `i`, or -1 if there is none.
+func (ba *BitArray) NextSet(i int) int {
+	if i < 0 {
+		i = 0
+	}
+	for ; i < ba.Size(); i++ {
+		// Skip a whole byte if no bit is set in the rest of it.
+		if ba.elems[i>>3]<<(i&7) == 0 {
+			i |= 7
+			continue
+		}
+		if GetBit(ba.elems, i) {
+			return i
+		}
+	}
+	return -1
+}
+
+// ForEach calls `fn` with the index of each set bit in ascending order until `fn` returns false.
+func (ba *BitArray) ForEach(fn func(i int) bool) {
+	for i := ba.NextSet(0); i >= 0; i = ba.NextSet(i + 1) {
+		if !fn(i) {
+			return
+		}
+	}
+}
+
+// Union returns a BitArray of the larger size with the bits set in either `ba` or `o`.
+func (ba *BitArray) Union(o *BitArray) *BitArray {
+	if ba.Size() < o.Size() {
+		ba, o = o, ba
+	}
+	c := ba.Copy()
+	if o.Size() > 0 {
+		OrInto(c.elems, c.elems, o.elems)
+	}
+	return c
+}
+
+// Intersection returns a BitArray of the smaller size with the bits set in both `ba` and `o`.
+func (ba *BitArray) Intersection(o *BitArray) *BitArray {
+	c := NewBitArray(min(ba.Size(), o.Size()))
+	if c.bits > 0 {
+		AndInto(c.elems, ba.elems, o.elems)
+		c.clearTrailingBits()
+	}
+	return c
+}
+
+// Difference returns a BitArray of the size of `ba` with the bits set in `ba` but not in `o`.
+func (ba *BitArray) Difference(o *BitArray) *BitArray {
+	c := ba.Copy()
+	if c.Size() == 0 || o.Size() == 0 {
+		return c
+	}
+	for i := 0; i < len(c.elems) && i < len(o.elems); i++ {
+		c.elems[i] &^= o.elems[i]
+	}
+	return c
+}
+
+// PickRandom returns the index of a set bit picked randomly using the `rand` package, or false if
+// no bit is set.
+func (ba *BitArray) PickRandom() (int, bool) {
+	cnt := ba.Count()
+	if cnt == 0 {
+		return 0, false
+	}
+
+	k := rand.Intn(cnt)
+	picked := -1
+	ba.ForEach(func(i int) bool {
+		if k == 0 {
+			picked = i
+			return false
+		}
+		k--
+		return true
+	})
+	return picked, true
+}
+
+// Copy returns a deep copy of the BitArray.
+func (ba *BitArray) Copy() *BitArray {
+	if ba == nil {
+		return nil
+	}
+	return &BitArray{
+		bits:  ba.bits,
+		elems: bytes.Clone(ba.elems),
+	}
+}
+
+// Bytes returns a copy of the underlying bytes, the unused trailing bits are always zero.
+func (ba *BitArray) Bytes() []byte {
+	if ba == nil {
+		return nil
+	}
+	return bytes.Clone(ba.elems)
+}
+
+// String returns the bits as a string of 'x' for set bits and '_' for unset bits.
+func (ba *BitArray) String() string {
+	if ba == nil {
+		return "nil-BitArray"
+	}
+	buf := make([]byte, ba.bits)
+	for i := range buf {
+		if GetBit(ba.elems, i) {
+			buf[i] = 'x'
+		} else {
+			buf[i] = '_'
+		}
+	}
+	return UnsafeString(buf)
+}
+
+// MarshalJSON implements the json.Marshaler interface. The encoding is a JSON quoted string of 'x'
+// for set bits and '_' for unset bits, a nil *BitArray is encoded as null. It has a value receiver
+// so that BitArray values, e.g. in struct fields, are encoded the same as pointers.
+func (ba BitArray) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + ba.String() + `"`), nil
+}
+
+// UnmarshalJSON implements the json.Umarshaler interface. It accepts the encoding of MarshalJSON,
+// or hexadecimal digits of the underlying bytes the same as HexBytes, in which case the size is
+// 8 times the number of bytes.
+func (ba *BitArray) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if len(data) < 2 || data[0] != '"' || data[len(data)-1] != '"' {
+		return fmt.Errorf("invalid BitArray string: %s", data)
+	}
+	s := data[1 : len(data)-1]
+
+	if bytes.IndexFunc(s, func(r rune) bool { return r != 'x' && r != '_' }) >= 0 {
+		var bz HexBytes
+		if err := bz.UnmarshalText(s); err != nil {
+			return fmt.Errorf("invalid BitArray string: %s", data)
+		}
+		*ba = BitArray{bits: 8 * len(bz), elems: bz}
+		return nil
+	}
+
+	ba2 := NewBitArray(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == 'x' {
+			ba2.Set(i)
+		}
+	}
+	*ba = *ba2
+	return nil
+}
+
+// clearTrailingBits clears the unused bits in the last byte.
+func (ba *BitArray) clearTrailingBits() {
+	if r := ba.bits & 7; r != 0 {
+		ba.elems[len(ba.elems)-1] &= ^byte(0xff >> r)
+	}
+}
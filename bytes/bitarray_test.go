@@ -0,0 +1,164 @@
+package bytes_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/daotl/guts/bytes"
+)
+
+func newBitArrayFromString(s string) *BitArray {
+	ba := NewBitArray(len(s))
+	for i, c := range s {
+		if c == 'x' {
+			ba.Set(i)
+		}
+	}
+	return ba
+}
+
+func TestBitArraySetClearTest(t *testing.T) {
+	assr := assert.New(t)
+
+	ba := NewBitArray(10)
+	assr.Equal(10, ba.Size())
+	assr.True(ba.Set(0))
+	assr.True(ba.Set(9))
+	assr.False(ba.Set(10))
+	assr.False(ba.Set(-1))
+	assr.True(ba.Test(9))
+	assr.False(ba.Test(8))
+	assr.False(ba.Test(100))
+	assr.Equal(2, ba.Count())
+	assr.Equal("x________x", ba.String())
+	assr.Equal([]byte{0x80, 0x40}, ba.Bytes())
+
+	assr.True(ba.Clear(0))
+	assr.False(ba.Clear(10))
+	assr.Equal("_________x", ba.String())
+
+	var nilBA *BitArray
+	assr.Equal(0, nilBA.Size())
+	assr.False(nilBA.Test(0))
+	assr.False(nilBA.Set(0))
+	assr.Panics(func() { NewBitArray(-1) })
+}
+
+func TestBitArrayIteration(t *testing.T) {
+	assr := assert.New(t)
+
+	ba := newBitArrayFromString("x_______x__________x_x")
+	assr.Equal(0, ba.NextSet(0))
+	assr.Equal(8, ba.NextSet(1))
+	assr.Equal(19, ba.NextSet(9))
+	assr.Equal(21, ba.NextSet(20))
+	assr.Equal(-1, ba.NextSet(22))
+
+	var set []int
+	ba.ForEach(func(i int) bool {
+		set = append(set, i)
+		return true
+	})
+	assr.Equal([]int{0, 8, 19, 21}, set)
+
+	set = nil
+	ba.ForEach(func(i int) bool {
+		set = append(set, i)
+		return len(set) < 2
+	})
+	assr.Equal([]int{0, 8}, set)
+}
+
+func TestBitArraySetOperations(t *testing.T) {
+	assr := assert.New(t)
+
+	a := newBitArrayFromString("xx__x_x__x")
+	b := newBitArrayFromString("x_x_xx_")
+
+	assr.Equal("xxx_xxx__x", a.Union(b).String())
+	assr.Equal("xxx_xxx__x", b.Union(a).String())
+	assr.Equal("x___x__", a.Intersection(b).String())
+	assr.Equal("x___x__", b.Intersection(a).String())
+	assr.Equal("_x____x__x", a.Difference(b).String())
+	assr.Equal("__x__x_", b.Difference(a).String())
+
+	// The operands are not modified.
+	assr.Equal("xx__x_x__x", a.String())
+	assr.Equal("x_x_xx_", b.String())
+
+	var nilBA *BitArray
+	assr.Equal(a.String(), a.Union(nilBA).String())
+	assr.Equal("", a.Intersection(nilBA).String())
+	assr.Equal(a.String(), a.Difference(nilBA).String())
+	assr.Nil(nilBA.Difference(a))
+}
+
+func TestBitArrayPickRandom(t *testing.T) {
+	assr := assert.New(t)
+
+	_, ok := NewBitArray(10).PickRandom()
+	assr.False(ok)
+
+	ba := newBitArrayFromString("_x___x_x_")
+	picked := map[int]bool{}
+	for i := 0; i < 200; i++ {
+		idx, ok := ba.PickRandom()
+		assr.True(ok)
+		assr.True(ba.Test(idx))
+		picked[idx] = true
+	}
+	assr.Len(picked, 3)
+}
+
+func TestBitArrayJSON(t *testing.T) {
+	req := require.New(t)
+
+	type TestStruct struct {
+		Votes *BitArray
+	}
+
+	ts := TestStruct{Votes: newBitArrayFromString("x_x__x")}
+	jsonBytes, err := json.Marshal(ts)
+	req.NoError(err)
+	req.Equal(`{"Votes":"x_x__x"}`, string(jsonBytes))
+
+	var ts2 TestStruct
+	req.NoError(json.Unmarshal(jsonBytes, &ts2))
+	req.Equal(ts, ts2)
+
+	jsonBytes, err = json.Marshal(TestStruct{})
+	req.NoError(err)
+	req.Equal(`{"Votes":null}`, string(jsonBytes))
+	req.NoError(json.Unmarshal(jsonBytes, &ts2))
+	req.Nil(ts2.Votes)
+
+	// Hex strings consistent with HexBytes are accepted.
+	req.NoError(json.Unmarshal([]byte(`{"Votes":"A5"}`), &ts2))
+	req.Equal("x_x__x_x", ts2.Votes.String())
+	req.NoError(json.Unmarshal([]byte(`{"Votes":"0x01"}`), &ts2))
+	req.Equal("_______x", ts2.Votes.String())
+
+	req.Error(json.Unmarshal([]byte(`{"Votes":"x_y"}`), &ts2))
+	req.Error(json.Unmarshal([]byte(`{"Votes":1}`), &ts2))
+
+	// BitArray values are encoded the same as pointers.
+	type ValueStruct struct {
+		Votes BitArray
+	}
+
+	vs := ValueStruct{Votes: *newBitArrayFromString("x_x__x")}
+	jsonBytes, err = json.Marshal(vs)
+	req.NoError(err)
+	req.Equal(`{"Votes":"x_x__x"}`, string(jsonBytes))
+
+	var vs2 ValueStruct
+	req.NoError(json.Unmarshal(jsonBytes, &vs2))
+	req.Equal(vs, vs2)
+
+	jsonBytes, err = json.Marshal(ValueStruct{})
+	req.NoError(err)
+	req.Equal(`{"Votes":""}`, string(jsonBytes))
+}
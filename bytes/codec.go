@@ -0,0 +1,295 @@
+package bytes
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// ErrShortBuffer is returned when the input is too short to decode a value from.
+var ErrShortBuffer = errors.New("short buffer")
+
+// shortBuffer returns an error wrapping ErrShortBuffer with the needed and available sizes.
+func shortBuffer(need, have int) error {
+	return fmt.Errorf("%w: need %d bytes, have %d", ErrShortBuffer, need, have)
+}
+
+// byteOrder is implemented by binary.LittleEndian, binary.BigEndian and binary.NativeEndian.
+type byteOrder interface {
+	binary.ByteOrder
+	binary.AppendByteOrder
+}
+
+// Codec converts integers from/to bytes in a specific byte order.
+//
+// Use LittleEndian, BigEndian or NativeEndian, the zero value is not usable.
+type Codec struct {
+	order byteOrder
+}
+
+var (
+	// LittleEndian is the little-endian Codec, the package-level conversion functions are
+	// shortcuts over it.
+	LittleEndian = Codec{order: binary.LittleEndian}
+
+	// BigEndian is the big-endian Codec, useful for wire protocols and keys whose byte order
+	// should equal their sort order.
+	BigEndian = Codec{order: binary.BigEndian}
+
+	// NativeEndian is the Codec using the native byte order of the machine.
+	NativeEndian = Codec{order: binary.NativeEndian}
+)
+
+// String returns the name of the byte order.
+func (c Codec) String() string {
+	return c.order.String()
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// FromU08 converts uint8 to bytes.
+func (c Codec) FromU08(val uint8) []byte {
+	return []byte{val}
+}
+
+// FromU16 converts uint16 to bytes.
+func (c Codec) FromU16(val uint16) []byte {
+	buffer := make([]byte, unsafe.Sizeof(uint16(0)))
+	c.order.PutUint16(buffer, val)
+	return buffer
+}
+
+// FromU32 converts uint32 to bytes.
+func (c Codec) FromU32(val uint32) []byte {
+	buffer := make([]byte, unsafe.Sizeof(uint32(0)))
+	c.order.PutUint32(buffer, val)
+	return buffer
+}
+
+// FromU64 converts uint64 to bytes.
+func (c Codec) FromU64(val uint64) []byte {
+	buffer := make([]byte, unsafe.Sizeof(uint64(0)))
+	c.order.PutUint64(buffer, val)
+	return buffer
+}
+
+// FromI08 converts int8 to bytes.
+func (c Codec) FromI08(val int8) []byte {
+	return c.FromU08(uint8(val))
+}
+
+// FromI16 converts int16 to bytes.
+func (c Codec) FromI16(val int16) []byte {
+	return c.FromU16(uint16(val))
+}
+
+// FromI32 converts int32 to bytes.
+func (c Codec) FromI32(val int32) []byte {
+	return c.FromU32(uint32(val))
+}
+
+// FromI64 converts int64 to bytes.
+func (c Codec) FromI64(val int64) []byte {
+	return c.FromU64(uint64(val))
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// ToU08 converts bytes to uint8, returns 0 if `val` is empty.
+func (c Codec) ToU08(val []byte) uint8 {
+	switch {
+	case len(val) == 0:
+		return uint8(0)
+	default:
+		return val[0]
+	}
+}
+
+// ToU16 converts bytes to uint16, panics if `val` is too short.
+func (c Codec) ToU16(val []byte) uint16 {
+	return c.order.Uint16(val)
+}
+
+// ToU32 converts bytes to uint32, panics if `val` is too short.
+func (c Codec) ToU32(val []byte) uint32 {
+	return c.order.Uint32(val)
+}
+
+// ToU64 converts bytes to uint64, panics if `val` is too short.
+func (c Codec) ToU64(val []byte) uint64 {
+	return c.order.Uint64(val)
+}
+
+// ToI08 converts bytes to int8, returns 0 if `val` is empty.
+func (c Codec) ToI08(val []byte) int8 {
+	return int8(c.ToU08(val))
+}
+
+// ToI16 converts bytes to int16, panics if `val` is too short.
+func (c Codec) ToI16(val []byte) int16 {
+	return int16(c.ToU16(val))
+}
+
+// ToI32 converts bytes to int32, panics if `val` is too short.
+func (c Codec) ToI32(val []byte) int32 {
+	return int32(c.ToU32(val))
+}
+
+// ToI64 converts bytes to int64, panics if `val` is too short.
+func (c Codec) ToI64(val []byte) int64 {
+	return int64(c.ToU64(val))
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// AppendU08 appends the bytes of uint8 to `dst` and returns the extended buffer.
+func (c Codec) AppendU08(dst []byte, val uint8) []byte {
+	return append(dst, val)
+}
+
+// AppendU16 appends the bytes of uint16 to `dst` and returns the extended buffer.
+func (c Codec) AppendU16(dst []byte, val uint16) []byte {
+	return c.order.AppendUint16(dst, val)
+}
+
+// AppendU32 appends the bytes of uint32 to `dst` and returns the extended buffer.
+func (c Codec) AppendU32(dst []byte, val uint32) []byte {
+	return c.order.AppendUint32(dst, val)
+}
+
+// AppendU64 appends the bytes of uint64 to `dst` and returns the extended buffer.
+func (c Codec) AppendU64(dst []byte, val uint64) []byte {
+	return c.order.AppendUint64(dst, val)
+}
+
+// AppendI08 appends the bytes of int8 to `dst` and returns the extended buffer.
+func (c Codec) AppendI08(dst []byte, val int8) []byte {
+	return c.AppendU08(dst, uint8(val))
+}
+
+// AppendI16 appends the bytes of int16 to `dst` and returns the extended buffer.
+func (c Codec) AppendI16(dst []byte, val int16) []byte {
+	return c.AppendU16(dst, uint16(val))
+}
+
+// AppendI32 appends the bytes of int32 to `dst` and returns the extended buffer.
+func (c Codec) AppendI32(dst []byte, val int32) []byte {
+	return c.AppendU32(dst, uint32(val))
+}
+
+// AppendI64 appends the bytes of int64 to `dst` and returns the extended buffer.
+func (c Codec) AppendI64(dst []byte, val int64) []byte {
+	return c.AppendU64(dst, uint64(val))
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// PutU08 puts the bytes of uint8 into `dst`, panics if `dst` is empty.
+func (c Codec) PutU08(dst []byte, val uint8) {
+	dst[0] = val
+}
+
+// PutU16 puts the bytes of uint16 into `dst`, panics if `dst` is too short.
+func (c Codec) PutU16(dst []byte, val uint16) {
+	c.order.PutUint16(dst, val)
+}
+
+// PutU32 puts the bytes of uint32 into `dst`, panics if `dst` is too short.
+func (c Codec) PutU32(dst []byte, val uint32) {
+	c.order.PutUint32(dst, val)
+}
+
+// PutU64 puts the bytes of uint64 into `dst`, panics if `dst` is too short.
+func (c Codec) PutU64(dst []byte, val uint64) {
+	c.order.PutUint64(dst, val)
+}
+
+// PutI08 puts the bytes of int8 into `dst`, panics if `dst` is empty.
+func (c Codec) PutI08(dst []byte, val int8) {
+	c.PutU08(dst, uint8(val))
+}
+
+// PutI16 puts the bytes of int16 into `dst`, panics if `dst` is too short.
+func (c Codec) PutI16(dst []byte, val int16) {
+	c.PutU16(dst, uint16(val))
+}
+
+// PutI32 puts the bytes of int32 into `dst`, panics if `dst` is too short.
+func (c Codec) PutI32(dst []byte, val int32) {
+	c.PutU32(dst, uint32(val))
+}
+
+// PutI64 puts the bytes of int64 into `dst`, panics if `dst` is too short.
+func (c Codec) PutI64(dst []byte, val int64) {
+	c.PutU64(dst, uint64(val))
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// ReadU08 reads uint8 from the beginning of `b` and returns it with the remaining bytes,
+// returns ErrShortBuffer if `b` is empty.
+func (c Codec) ReadU08(b []byte) (uint8, []byte, error) {
+	if len(b) < 1 {
+		return 0, b, shortBuffer(1, len(b))
+	}
+	return b[0], b[1:], nil
+}
+
+// ReadU16 reads uint16 from the beginning of `b` and returns it with the remaining bytes,
+// returns ErrShortBuffer if `b` is too short.
+func (c Codec) ReadU16(b []byte) (uint16, []byte, error) {
+	const size = int(unsafe.Sizeof(uint16(0)))
+	if len(b) < size {
+		return 0, b, shortBuffer(size, len(b))
+	}
+	return c.order.Uint16(b), b[size:], nil
+}
+
+// ReadU32 reads uint32 from the beginning of `b` and returns it with the remaining bytes,
+// returns ErrShortBuffer if `b` is too short.
+func (c Codec) ReadU32(b []byte) (uint32, []byte, error) {
+	const size = int(unsafe.Sizeof(uint32(0)))
+	if len(b) < size {
+		return 0, b, shortBuffer(size, len(b))
+	}
+	return c.order.Uint32(b), b[size:], nil
+}
+
+// ReadU64 reads uint64 from the beginning of `b` and returns it with the remaining bytes,
+// returns ErrShortBuffer if `b` is too short.
+func (c Codec) ReadU64(b []byte) (uint64, []byte, error) {
+	const size = int(unsafe.Sizeof(uint64(0)))
+	if len(b) < size {
+		return 0, b, shortBuffer(size, len(b))
+	}
+	return c.order.Uint64(b), b[size:], nil
+}
+
+// ReadI08 reads int8 from the beginning of `b` and returns it with the remaining bytes,
+// returns ErrShortBuffer if `b` is empty.
+func (c Codec) ReadI08(b []byte) (int8, []byte, error) {
+	val, rest, err := c.ReadU08(b)
+	return int8(val), rest, err
+}
+
+// ReadI16 reads int16 from the beginning of `b` and returns it with the remaining bytes,
+// returns ErrShortBuffer if `b` is too short.
+func (c Codec) ReadI16(b []byte) (int16, []byte, error) {
+	val, rest, err := c.ReadU16(b)
+	return int16(val), rest, err
+}
+
+// ReadI32 reads int32 from the beginning of `b` and returns it with the remaining bytes,
+// returns ErrShortBuffer if `b` is too short.
+func (c Codec) ReadI32(b []byte) (int32, []byte, error) {
+	val, rest, err := c.ReadU32(b)
+	return int32(val), rest, err
+}
+
+// ReadI64 reads int64 from the beginning of `b` and returns it with the remaining bytes,
+// returns ErrShortBuffer if `b` is too short.
+func (c Codec) ReadI64(b []byte) (int64, []byte, error) {
+	val, rest, err := c.ReadU64(b)
+	return int64(val), rest, err
+}
@@ -0,0 +1,102 @@
+package bytes_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/daotl/guts/bytes"
+)
+
+func TestCodecByteOrder(t *testing.T) {
+	assr := assert.New(t)
+
+	assr.Equal([]byte{0x04, 0x03, 0x02, 0x01}, LittleEndian.FromU32(0x01020304))
+	assr.Equal([]byte{0x01, 0x02, 0x03, 0x04}, BigEndian.FromU32(0x01020304))
+	assr.Equal(binary.NativeEndian.AppendUint32(nil, 0x01020304), NativeEndian.FromU32(0x01020304))
+
+	assr.Equal([]byte{0x01, 0x02}, BigEndian.FromU16(0x0102))
+	assr.Equal([]byte{0, 0, 0, 0, 0, 0, 0x01, 0x02}, BigEndian.FromU64(0x0102))
+	assr.Equal([]byte{0xff, 0xfe}, BigEndian.FromI16(-2))
+	assr.Equal("BigEndian", BigEndian.String())
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	assr := assert.New(t)
+
+	for _, c := range []Codec{LittleEndian, BigEndian, NativeEndian} {
+		assr.Equal(uint8(0xab), c.ToU08(c.FromU08(0xab)))
+		assr.Equal(uint16(0xabcd), c.ToU16(c.FromU16(0xabcd)))
+		assr.Equal(uint32(0xabcdef01), c.ToU32(c.FromU32(0xabcdef01)))
+		assr.Equal(uint64(0xabcdef0123456789), c.ToU64(c.FromU64(0xabcdef0123456789)))
+		assr.Equal(int8(-8), c.ToI08(c.FromI08(-8)))
+		assr.Equal(int16(-16), c.ToI16(c.FromI16(-16)))
+		assr.Equal(int32(-32), c.ToI32(c.FromI32(-32)))
+		assr.Equal(int64(-64), c.ToI64(c.FromI64(-64)))
+		assr.Equal(uint8(0), c.ToU08(nil))
+	}
+}
+
+func TestCodecAppendAndPut(t *testing.T) {
+	assr := assert.New(t)
+
+	buf := []byte{0xff}
+	buf = BigEndian.AppendU08(buf, 0x01)
+	buf = BigEndian.AppendU16(buf, 0x0203)
+	buf = BigEndian.AppendI32(buf, 0x04050607)
+	buf = LittleEndian.AppendU64(buf, 0x08)
+	assr.Equal([]byte{0xff, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0, 0, 0, 0, 0, 0, 0}, buf)
+
+	put := make([]byte, 8)
+	BigEndian.PutU64(put, 0x0102030405060708)
+	assr.Equal(BigEndian.FromU64(0x0102030405060708), put)
+	LittleEndian.PutI16(put[2:], -2)
+	assr.Equal([]byte{0x01, 0x02, 0xfe, 0xff, 0x05, 0x06, 0x07, 0x08}, put)
+	BigEndian.PutU08(put, 0xaa)
+	assr.Equal(byte(0xaa), put[0])
+}
+
+func TestPackageLevelConvertersAreLittleEndian(t *testing.T) {
+	assr := assert.New(t)
+
+	assr.Equal(LittleEndian.FromU64(0x0102030405060708), FromU64(0x0102030405060708))
+	assr.Equal(LittleEndian.FromI32(-3), FromI32(-3))
+	assr.Equal(uint16(0x0201), ToU16([]byte{0x01, 0x02}))
+	assr.Equal(int64(-1), ToI64(FromI64(-1)))
+}
+
+func TestCodecRead(t *testing.T) {
+	assr := assert.New(t)
+
+	frame := Concat(BigEndian.FromU16(0x0102), BigEndian.FromU32(0x03040506), []byte{0x07})
+
+	u16, rest, err := BigEndian.ReadU16(frame)
+	assr.NoError(err)
+	assr.Equal(uint16(0x0102), u16)
+	u32, rest, err := BigEndian.ReadU32(rest)
+	assr.NoError(err)
+	assr.Equal(uint32(0x03040506), u32)
+	i8, rest, err := BigEndian.ReadI08(rest)
+	assr.NoError(err)
+	assr.Equal(int8(0x07), i8)
+	assr.Empty(rest)
+
+	_, rest, err = BigEndian.ReadU08(rest)
+	assr.ErrorIs(err, ErrShortBuffer)
+	assr.Empty(rest)
+
+	short := []byte{0x01, 0x02, 0x03}
+	_, rest, err = LittleEndian.ReadU64(short)
+	assr.ErrorIs(err, ErrShortBuffer)
+	assr.Equal(short, rest)
+	_, _, err = ReadU32(short)
+	assr.ErrorIs(err, ErrShortBuffer)
+	_, _, err = ReadI16(short[:1])
+	assr.ErrorIs(err, ErrShortBuffer)
+
+	i64, rest, err := ReadI64(FromI64(-42))
+	assr.NoError(err)
+	assr.Equal(int64(-42), i64)
+	assr.Empty(rest)
+}
@@ -3,23 +3,19 @@
 package bytes
 
 import (
-	"encoding/binary"
 	"encoding/hex"
-	"unsafe"
 )
 
 /////////////////////////////////////////////////////////////////////////////
 
 // FromU08 converts uint8 to bytes(little endian).
 func FromU08(val uint8) []byte {
-	return []byte{val}
+	return LittleEndian.FromU08(val)
 }
 
 // FromU16 converts uint16 to bytes(little endian).
 func FromU16(val uint16) []byte {
-	buffer := make([]byte, unsafe.Sizeof(uint16(0)))
-	binary.LittleEndian.PutUint16(buffer, val)
-	return buffer
+	return LittleEndian.FromU16(val)
 
 	/* References:
 	[Convert an integer to a byte array]
@@ -29,83 +25,124 @@ func FromU16(val uint16) []byte {
 
 // FromU32 converts uint32 to bytes(little endian).
 func FromU32(val uint32) []byte {
-	buffer := make([]byte, unsafe.Sizeof(uint32(0)))
-	binary.LittleEndian.PutUint32(buffer, val)
-	return buffer
+	return LittleEndian.FromU32(val)
 }
 
 // FromU64 converts uint64 to bytes(little endian).
 func FromU64(val uint64) []byte {
-	buffer := make([]byte, unsafe.Sizeof(uint64(0)))
-	binary.LittleEndian.PutUint64(buffer, val)
-	return buffer
+	return LittleEndian.FromU64(val)
 }
 
 // FromI08 converts int8 to bytes(little endian).
 func FromI08(val int8) []byte {
-	return FromU08(uint8(val))
+	return LittleEndian.FromI08(val)
 }
 
 // FromI16 converts int16 to bytes(little endian).
 func FromI16(val int16) []byte {
-	return FromU16(uint16(val))
+	return LittleEndian.FromI16(val)
 }
 
 // FromI32 converts int32 to bytes(little endian).
 func FromI32(val int32) []byte {
-	return FromU32(uint32(val))
+	return LittleEndian.FromI32(val)
 }
 
 // FromI64 converts int64 to bytes(little endian).
 func FromI64(val int64) []byte {
-	return FromU64(uint64(val))
+	return LittleEndian.FromI64(val)
 }
 
 /////////////////////////////////////////////////////////////////////////////
 
 // ToU08 convert bytes(little endian) to uint8.
 func ToU08(val []byte) uint8 {
-	switch {
-	case len(val) == 0:
-		return uint8(0)
-	default:
-		return val[0]
-	}
+	return LittleEndian.ToU08(val)
 }
 
 // ToU16 convert bytes(little endian) to uint16.
 func ToU16(val []byte) uint16 {
-	return binary.LittleEndian.Uint16(val)
+	return LittleEndian.ToU16(val)
 }
 
 // ToU32 convert bytes(little endian) to uint32.
 func ToU32(val []byte) uint32 {
-	return binary.LittleEndian.Uint32(val)
+	return LittleEndian.ToU32(val)
 }
 
-// ToU64 convert bytes(little endian) to uint32.
+// ToU64 convert bytes(little endian) to uint64.
 func ToU64(val []byte) uint64 {
-	return binary.LittleEndian.Uint64(val)
+	return LittleEndian.ToU64(val)
 }
 
 // ToI08 converts bytes to int8 (little endian).
 func ToI08(val []byte) int8 {
-	return int8(ToU08(val))
+	return LittleEndian.ToI08(val)
 }
 
 // ToI16 converts bytes to int16 (little endian).
 func ToI16(val []byte) int16 {
-	return int16(ToU16(val))
+	return LittleEndian.ToI16(val)
 }
 
 // ToI32 converts bytes to int32 (little endian).
 func ToI32(val []byte) int32 {
-	return int32(ToU32(val))
+	return LittleEndian.ToI32(val)
 }
 
 // ToI64 converts bytes to int64 (little endian).
 func ToI64(val []byte) int64 {
-	return int64(ToU64(val))
+	return LittleEndian.ToI64(val)
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// ReadU08 reads uint8 from the beginning of `b` and returns it with the remaining bytes,
+// returns ErrShortBuffer if `b` is empty.
+func ReadU08(b []byte) (uint8, []byte, error) {
+	return LittleEndian.ReadU08(b)
+}
+
+// ReadU16 reads uint16(little endian) from the beginning of `b` and returns it with the
+// remaining bytes, returns ErrShortBuffer if `b` is too short.
+func ReadU16(b []byte) (uint16, []byte, error) {
+	return LittleEndian.ReadU16(b)
+}
+
+// ReadU32 reads uint32(little endian) from the beginning of `b` and returns it with the
+// remaining bytes, returns ErrShortBuffer if `b` is too short.
+func ReadU32(b []byte) (uint32, []byte, error) {
+	return LittleEndian.ReadU32(b)
+}
+
+// ReadU64 reads uint64(little endian) from the beginning of `b` and returns it with the
+// remaining bytes, returns ErrShortBuffer if `b` is too short.
+func ReadU64(b []byte) (uint64, []byte, error) {
+	return LittleEndian.ReadU64(b)
+}
+
+// ReadI08 reads int8 from the beginning of `b` and returns it with the remaining bytes,
+// returns ErrShortBuffer if `b` is empty.
+func ReadI08(b []byte) (int8, []byte, error) {
+	return LittleEndian.ReadI08(b)
+}
+
+// ReadI16 reads int16(little endian) from the beginning of `b` and returns it with the
+// remaining bytes, returns ErrShortBuffer if `b` is too short.
+func ReadI16(b []byte) (int16, []byte, error) {
+	return LittleEndian.ReadI16(b)
+}
+
+// ReadI32 reads int32(little endian) from the beginning of `b` and returns it with the
+// remaining bytes, returns ErrShortBuffer if `b` is too short.
+func ReadI32(b []byte) (int32, []byte, error) {
+	return LittleEndian.ReadI32(b)
+}
+
+// ReadI64 reads int64(little endian) from the beginning of `b` and returns it with the
+// remaining bytes, returns ErrShortBuffer if `b` is too short.
+func ReadI64(b []byte) (int64, []byte, error) {
+	return LittleEndian.ReadI64(b)
 }
 
 /////////////////////////////////////////////////////////////////////////////
@@ -0,0 +1,105 @@
+package bytes
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// dumpWidth is the number of bytes per line of Dump and Diff.
+const dumpWidth = 16
+
+// Dump returns the canonical hex+ASCII display of `b` the same as `hexdump -C`: each line has the
+// offset, 16 bytes in hexadecimal digits and their printable ASCII characters. Consecutive
+// identical lines are squeezed into a single "*" line, and the last line is the length of `b`.
+func Dump(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	squeezed := false
+	for off := 0; off < len(b); off += dumpWidth {
+		line := b[off:min(off+dumpWidth, len(b))]
+		if off > 0 && len(line) == dumpWidth && bytes.Equal(line, b[off-dumpWidth:off]) {
+			if !squeezed {
+				sb.WriteString("*\n")
+				squeezed = true
+			}
+			continue
+		}
+		squeezed = false
+		writeDumpLine(&sb, off, line)
+		sb.WriteByte('\n')
+	}
+	fmt.Fprintf(&sb, "%08x\n", len(b))
+	return sb.String()
+}
+
+// Diff returns the lines of the Dump of `a` and `b` at the same offsets that differ, the line of
+// `a` is prefixed with "-", the line of `b` with "+", followed by a line with "^^" under each
+// differing byte. Returns an empty string if `a` and `b` are equal.
+func Diff(a, b []byte) string {
+	var sb strings.Builder
+	for off := 0; off < max(len(a), len(b)); off += dumpWidth {
+		la := a[min(off, len(a)):min(off+dumpWidth, len(a))]
+		lb := b[min(off, len(b)):min(off+dumpWidth, len(b))]
+		if bytes.Equal(la, lb) {
+			continue
+		}
+
+		if len(la) > 0 {
+			sb.WriteByte('-')
+			writeDumpLine(&sb, off, la)
+			sb.WriteByte('\n')
+		}
+		if len(lb) > 0 {
+			sb.WriteByte('+')
+			writeDumpLine(&sb, off, lb)
+			sb.WriteByte('\n')
+		}
+
+		marker := []byte(strings.Repeat(" ", 1+8+2+dumpWidth*3+1))
+		for i := 0; i < max(len(la), len(lb)); i++ {
+			if i < len(la) && i < len(lb) && la[i] == lb[i] {
+				continue
+			}
+			pos := 1 + 8 + 2 + i*3
+			if i >= dumpWidth/2 {
+				pos++
+			}
+			marker[pos], marker[pos+1] = '^', '^'
+		}
+		sb.Write(bytes.TrimRight(marker, " "))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// writeDumpLine writes a line of Dump without the trailing newline.
+func writeDumpLine(sb *strings.Builder, off int, line []byte) {
+	const digits = "0123456789abcdef"
+
+	fmt.Fprintf(sb, "%08x  ", off)
+	for i := 0; i < dumpWidth; i++ {
+		if i == dumpWidth/2 {
+			sb.WriteByte(' ')
+		}
+		if i < len(line) {
+			sb.WriteByte(digits[line[i]>>4])
+			sb.WriteByte(digits[line[i]&0x0f])
+			sb.WriteByte(' ')
+		} else {
+			sb.WriteString("   ")
+		}
+	}
+
+	sb.WriteString(" |")
+	for _, c := range line {
+		if c < 0x20 || c > 0x7e {
+			c = '.'
+		}
+		sb.WriteByte(c)
+	}
+	sb.WriteByte('|')
+}
@@ -0,0 +1,54 @@
+package bytes_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/daotl/guts/bytes"
+)
+
+func TestDump(t *testing.T) {
+	assr := assert.New(t)
+
+	assr.Equal("", Dump(nil))
+	assr.Equal("00000000  61                                                |a|\n"+
+		"00000001\n", Dump([]byte("a")))
+
+	b := append([]byte("Hello World!\n\x00\x00\x00"), make([]byte, 40)...)
+	b = append(b, "tail"...)
+	assr.Equal(""+
+		"00000000  48 65 6c 6c 6f 20 57 6f  72 6c 64 21 0a 00 00 00  |Hello World!....|\n"+
+		"00000010  00 00 00 00 00 00 00 00  00 00 00 00 00 00 00 00  |................|\n"+
+		"*\n"+
+		"00000030  00 00 00 00 00 00 00 00  74 61 69 6c              |........tail|\n"+
+		"0000003c\n", Dump(b))
+
+	assr.Equal(Dump(b), fmt.Sprintf("%+v", HexBytes(b)))
+	assr.Equal(HexBytes(b).String(), fmt.Sprintf("%v", HexBytes(b)))
+}
+
+func TestDiff(t *testing.T) {
+	assr := assert.New(t)
+
+	a := []byte("Hello World!\n\x00\x00\x00tail")
+	assr.Equal("", Diff(a, a))
+	assr.Equal("", Diff(nil, []byte{}))
+
+	b := append([]byte{}, a...)
+	b[3] = 'X'
+	b = b[:len(b)-2]
+	assr.Equal(""+
+		"-00000000  48 65 6c 6c 6f 20 57 6f  72 6c 64 21 0a 00 00 00  |Hello World!....|\n"+
+		"+00000000  48 65 6c 58 6f 20 57 6f  72 6c 64 21 0a 00 00 00  |HelXo World!....|\n"+
+		"                    ^^\n"+
+		"-00000010  74 61 69 6c                                       |tail|\n"+
+		"+00000010  74 61                                             |ta|\n"+
+		"                 ^^ ^^\n", Diff(a, b))
+
+	// Lines present in only one of the buffers.
+	assr.Equal(""+
+		"+00000010  74 61 69 6c                                       |tail|\n"+
+		"           ^^ ^^ ^^ ^^\n", Diff(a[:16], a))
+}
@@ -0,0 +1,278 @@
+package bytes
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrHashLength is returned when converting or decoding bytes of a wrong length into a fixed-size
+// hash.
+var ErrHashLength = errors.New("invalid hash length")
+
+// setHash copies `src` into the fixed-size hash `dst`, returns ErrHashLength if their lengths differ.
+func setHash(dst []byte, src []byte) error {
+	if len(src) != len(dst) {
+		return fmt.Errorf("%w: want %d bytes, got %d", ErrHashLength, len(dst), len(src))
+	}
+	copy(dst, src)
+	return nil
+}
+
+// unmarshalHashJSON decodes a JSON quoted string of hexadecimal digits into the fixed-size hash
+// `dst`, leaves `dst` untouched if `data` is JSON null.
+func unmarshalHashJSON(dst []byte, data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	var bz HexBytes
+	if err := bz.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	return setHash(dst, bz)
+}
+
+// unmarshalHashText decodes hexadecimal digits into the fixed-size hash `dst`.
+func unmarshalHashText(dst []byte, text []byte) error {
+	bz, err := decodeHex(text)
+	if err != nil {
+		return err
+	}
+	return setHash(dst, bz)
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// Hash20 is a 20-byte fixed-size hash, it has the same JSON/text/fmt behavior as HexBytes, and
+// can be used as a map key.
+type Hash20 [20]byte
+
+var (
+	_ json.Marshaler           = Hash20{}
+	_ json.Unmarshaler         = &Hash20{}
+	_ encoding.TextMarshaler   = Hash20{}
+	_ encoding.TextUnmarshaler = &Hash20{}
+)
+
+// Hash20FromBytes converts `b` to a Hash20, returns ErrHashLength if `b` isn't 20 bytes long.
+func Hash20FromBytes(b []byte) (Hash20, error) {
+	var h Hash20
+	err := setHash(h[:], b)
+	return h, err
+}
+
+// ParseHash20 decodes hexadecimal digits in either case with an optional 0x prefix into a Hash20,
+// returns ErrHashLength if the decoded bytes aren't 20 bytes long.
+func ParseHash20(s string) (Hash20, error) {
+	var h Hash20
+	err := h.UnmarshalText([]byte(s))
+	return h, err
+}
+
+// MarshalJSON implements the json.Marshaler interface, see HexBytes.MarshalJSON.
+func (h Hash20) MarshalJSON() ([]byte, error) {
+	return HexBytes(h[:]).MarshalJSON()
+}
+
+// UnmarshalJSON implements the json.Umarshaler interface.
+func (h *Hash20) UnmarshalJSON(data []byte) error {
+	return unmarshalHashJSON(h[:], data)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, see HexBytes.MarshalText.
+func (h Hash20) MarshalText() ([]byte, error) {
+	return HexBytes(h[:]).MarshalText()
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (h *Hash20) UnmarshalText(text []byte) error {
+	return unmarshalHashText(h[:], text)
+}
+
+// Bytes returns the hash as bytes.
+func (h Hash20) Bytes() []byte {
+	return h[:]
+}
+
+// HexBytes converts the hash to HexBytes.
+func (h Hash20) HexBytes() HexBytes {
+	return h[:]
+}
+
+// IsZero reports whether all bytes of the hash are zero.
+func (h Hash20) IsZero() bool {
+	return h == Hash20{}
+}
+
+// Compare returns an integer comparing two hashes lexicographically.
+// The result will be 0 if h == other, -1 if h < other, and +1 if h > other.
+func (h Hash20) Compare(other Hash20) int {
+	return bytes.Compare(h[:], other[:])
+}
+
+func (h Hash20) String() string {
+	return HexBytes(h[:]).String()
+}
+
+// Format is the same as HexBytes.Format.
+func (h Hash20) Format(s fmt.State, verb rune) {
+	formatHex(h[:], s, verb, true, false)
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// Hash32 is a 32-byte fixed-size hash, it has the same JSON/text/fmt behavior as HexBytes, and
+// can be used as a map key.
+type Hash32 [32]byte
+
+var (
+	_ json.Marshaler           = Hash32{}
+	_ json.Unmarshaler         = &Hash32{}
+	_ encoding.TextMarshaler   = Hash32{}
+	_ encoding.TextUnmarshaler = &Hash32{}
+)
+
+// Hash32FromBytes converts `b` to a Hash32, returns ErrHashLength if `b` isn't 32 bytes long.
+func Hash32FromBytes(b []byte) (Hash32, error) {
+	var h Hash32
+	err := setHash(h[:], b)
+	return h, err
+}
+
+// ParseHash32 decodes hexadecimal digits in either case with an optional 0x prefix into a Hash32,
+// returns ErrHashLength if the decoded bytes aren't 32 bytes long.
+func ParseHash32(s string) (Hash32, error) {
+	var h Hash32
+	err := h.UnmarshalText([]byte(s))
+	return h, err
+}
+
+// MarshalJSON implements the json.Marshaler interface, see HexBytes.MarshalJSON.
+func (h Hash32) MarshalJSON() ([]byte, error) {
+	return HexBytes(h[:]).MarshalJSON()
+}
+
+// UnmarshalJSON implements the json.Umarshaler interface.
+func (h *Hash32) UnmarshalJSON(data []byte) error {
+	return unmarshalHashJSON(h[:], data)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, see HexBytes.MarshalText.
+func (h Hash32) MarshalText() ([]byte, error) {
+	return HexBytes(h[:]).MarshalText()
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (h *Hash32) UnmarshalText(text []byte) error {
+	return unmarshalHashText(h[:], text)
+}
+
+// Bytes returns the hash as bytes.
+func (h Hash32) Bytes() []byte {
+	return h[:]
+}
+
+// HexBytes converts the hash to HexBytes.
+func (h Hash32) HexBytes() HexBytes {
+	return h[:]
+}
+
+// IsZero reports whether all bytes of the hash are zero.
+func (h Hash32) IsZero() bool {
+	return h == Hash32{}
+}
+
+// Compare returns an integer comparing two hashes lexicographically.
+// The result will be 0 if h == other, -1 if h < other, and +1 if h > other.
+func (h Hash32) Compare(other Hash32) int {
+	return bytes.Compare(h[:], other[:])
+}
+
+func (h Hash32) String() string {
+	return HexBytes(h[:]).String()
+}
+
+// Format is the same as HexBytes.Format.
+func (h Hash32) Format(s fmt.State, verb rune) {
+	formatHex(h[:], s, verb, true, false)
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// Hash64 is a 64-byte fixed-size hash, it has the same JSON/text/fmt behavior as HexBytes, and
+// can be used as a map key.
+type Hash64 [64]byte
+
+var (
+	_ json.Marshaler           = Hash64{}
+	_ json.Unmarshaler         = &Hash64{}
+	_ encoding.TextMarshaler   = Hash64{}
+	_ encoding.TextUnmarshaler = &Hash64{}
+)
+
+// Hash64FromBytes converts `b` to a Hash64, returns ErrHashLength if `b` isn't 64 bytes long.
+func Hash64FromBytes(b []byte) (Hash64, error) {
+	var h Hash64
+	err := setHash(h[:], b)
+	return h, err
+}
+
+// ParseHash64 decodes hexadecimal digits in either case with an optional 0x prefix into a Hash64,
+// returns ErrHashLength if the decoded bytes aren't 64 bytes long.
+func ParseHash64(s string) (Hash64, error) {
+	var h Hash64
+	err := h.UnmarshalText([]byte(s))
+	return h, err
+}
+
+// MarshalJSON implements the json.Marshaler interface, see HexBytes.MarshalJSON.
+func (h Hash64) MarshalJSON() ([]byte, error) {
+	return HexBytes(h[:]).MarshalJSON()
+}
+
+// UnmarshalJSON implements the json.Umarshaler interface.
+func (h *Hash64) UnmarshalJSON(data []byte) error {
+	return unmarshalHashJSON(h[:], data)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, see HexBytes.MarshalText.
+func (h Hash64) MarshalText() ([]byte, error) {
+	return HexBytes(h[:]).MarshalText()
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (h *Hash64) UnmarshalText(text []byte) error {
+	return unmarshalHashText(h[:], text)
+}
+
+// Bytes returns the hash as bytes.
+func (h Hash64) Bytes() []byte {
+	return h[:]
+}
+
+// HexBytes converts the hash to HexBytes.
+func (h Hash64) HexBytes() HexBytes {
+	return h[:]
+}
+
+// IsZero reports whether all bytes of the hash are zero.
+func (h Hash64) IsZero() bool {
+	return h == Hash64{}
+}
+
+// Compare returns an integer comparing two hashes lexicographically.
+// The result will be 0 if h == other, -1 if h < other, and +1 if h > other.
+func (h Hash64) Compare(other Hash64) int {
+	return bytes.Compare(h[:], other[:])
+}
+
+func (h Hash64) String() string {
+	return HexBytes(h[:]).String()
+}
+
+// Format is the same as HexBytes.Format.
+func (h Hash64) Format(s fmt.State, verb rune) {
+	formatHex(h[:], s, verb, true, false)
+}
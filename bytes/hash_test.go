@@ -0,0 +1,95 @@
+package bytes_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/daotl/guts/bytes"
+)
+
+func TestHashFromBytes(t *testing.T) {
+	req := require.New(t)
+
+	b := make([]byte, 32)
+	b[0], b[31] = 0xab, 0xcd
+	h, err := Hash32FromBytes(b)
+	req.NoError(err)
+	req.Equal(b, h.Bytes())
+	req.Equal(HexBytes(b), h.HexBytes())
+	req.False(h.IsZero())
+	req.True(Hash32{}.IsZero())
+
+	_, err = Hash32FromBytes(b[:31])
+	req.ErrorIs(err, ErrHashLength)
+	_, err = Hash20FromBytes(b)
+	req.ErrorIs(err, ErrHashLength)
+	_, err = Hash64FromBytes(append(b, b...))
+	req.NoError(err)
+}
+
+func TestParseHash(t *testing.T) {
+	req := require.New(t)
+
+	s := strings.Repeat("ab", 20)
+	h, err := ParseHash20(s)
+	req.NoError(err)
+	req.Equal(strings.ToUpper(s), h.String())
+	h2, err := ParseHash20("0x" + strings.ToUpper(s))
+	req.NoError(err)
+	req.Equal(h, h2)
+
+	_, err = ParseHash20(s + "ab")
+	req.ErrorIs(err, ErrHashLength)
+	_, err = ParseHash32(s)
+	req.ErrorIs(err, ErrHashLength)
+	_, err = ParseHash20(s[:39] + "z")
+	req.Error(err)
+}
+
+func TestHashJSON(t *testing.T) {
+	req := require.New(t)
+
+	type TestStruct struct {
+		H  Hash20
+		HP *Hash32
+		M  map[Hash20]int
+	}
+
+	h20, err := ParseHash20(strings.Repeat("01", 20))
+	req.NoError(err)
+	h32 := Hash32{0xff}
+	ts := TestStruct{H: h20, HP: &h32, M: map[Hash20]int{h20: 1}}
+
+	jsonBytes, err := json.Marshal(ts)
+	req.NoError(err)
+	req.Equal(fmt.Sprintf(`{"H":"%s","HP":"FF%s","M":{"%s":1}}`,
+		strings.Repeat("01", 20), strings.Repeat("00", 31), strings.Repeat("01", 20)), string(jsonBytes))
+
+	var ts2 TestStruct
+	req.NoError(json.Unmarshal(jsonBytes, &ts2))
+	req.Equal(ts, ts2)
+
+	req.NoError(json.Unmarshal([]byte(`{"H":null}`), &ts2))
+	req.Equal(h20, ts2.H)
+	req.ErrorIs(json.Unmarshal([]byte(`{"H":"0102"}`), &ts2), ErrHashLength)
+	req.Error(json.Unmarshal([]byte(`{"H":1}`), &ts2))
+}
+
+func TestHashCompareAndFormat(t *testing.T) {
+	assr := assert.New(t)
+
+	a, b := Hash32{0x01}, Hash32{0x02}
+	assr.Equal(-1, a.Compare(b))
+	assr.Equal(1, b.Compare(a))
+	assr.Equal(0, a.Compare(a))
+
+	h := Hash64{0xab, 0xcd}
+	assr.Equal(h.String(), fmt.Sprintf("%v", h))
+	assr.Equal("abcd0...", fmt.Sprintf("%8x", h))
+	assr.Equal("0xABCD"+strings.Repeat("00", 62), fmt.Sprintf("%#X", h))
+}
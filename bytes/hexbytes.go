@@ -4,6 +4,9 @@ package bytes
 
 import (
 	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -11,11 +14,20 @@ import (
 )
 
 // HexBytes enables HEX-encoding for json/encoding.
+//
+// It's encoded in uppercase hexadecimal digits and can be decoded from either case with an
+// optional 0x prefix, use Hex0xBytes for lowercase output with 0x prefix.
 type HexBytes []byte
 
 var (
-	_ json.Marshaler   = HexBytes{}
-	_ json.Unmarshaler = &HexBytes{}
+	_ json.Marshaler             = HexBytes{}
+	_ json.Unmarshaler           = &HexBytes{}
+	_ encoding.TextMarshaler     = HexBytes{}
+	_ encoding.TextUnmarshaler   = &HexBytes{}
+	_ encoding.BinaryMarshaler   = HexBytes{}
+	_ encoding.BinaryUnmarshaler = &HexBytes{}
+	_ sql.Scanner                = &HexBytes{}
+	_ driver.Valuer              = HexBytes{}
 )
 
 // Marshal needed for protobuf compatibility
@@ -34,16 +46,9 @@ func (bz *HexBytes) Unmarshal(data []byte) error {
 func (bz HexBytes) MarshalJSON() ([]byte, error) {
 	size := hex.EncodedLen(len(bz)) + 2 // +2 for quotation marks
 	buf := make([]byte, size)
-	hex.Encode(buf[1:], []byte(bz))
+	encodeUpperHex(buf[1:], bz)
 	buf[0] = '"'
 	buf[size-1] = '"'
-
-	// Ensure letter digits are capitalized.
-	for i := 1; i < size-1; i++ {
-		if buf[i] >= 'a' && buf[i] <= 'f' {
-			buf[i] = 'A' + (buf[i] - 'a')
-		}
-	}
 	return buf, nil
 }
 
@@ -57,7 +62,21 @@ func (bz *HexBytes) UnmarshalJSON(data []byte) error {
 		return fmt.Errorf("invalid hex string: %s", data)
 	}
 
-	bz2, err := hex.DecodeString(string(data[1 : len(data)-1]))
+	return bz.UnmarshalText(data[1 : len(data)-1])
+}
+
+// MarshalText implements the encoding.TextMarshaler interface. The encoding is uppercase
+// hexadecimal digits.
+func (bz HexBytes) MarshalText() ([]byte, error) {
+	buf := make([]byte, hex.EncodedLen(len(bz)))
+	encodeUpperHex(buf, bz)
+	return buf, nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface. It accepts hexadecimal digits
+// in either case with an optional 0x prefix.
+func (bz *HexBytes) UnmarshalText(text []byte) error {
+	bz2, err := decodeHex(text)
 	if err != nil {
 		return err
 	}
@@ -67,23 +86,187 @@ func (bz *HexBytes) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalBinary implements the encoding.BinaryMarshaler interface by returning a copy of the bytes.
+func (bz HexBytes) MarshalBinary() ([]byte, error) {
+	return bytes.Clone(bz), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface by copying `data`.
+func (bz *HexBytes) UnmarshalBinary(data []byte) error {
+	*bz = bytes.Clone(data)
+	return nil
+}
+
+// Value implements the driver.Valuer interface, HexBytes is stored as raw bytes and nil as NULL.
+func (bz HexBytes) Value() (driver.Value, error) {
+	if bz == nil {
+		return nil, nil
+	}
+	return []byte(bz), nil
+}
+
+// Scan implements the sql.Scanner interface. It accepts raw bytes, a hex string or NULL.
+func (bz *HexBytes) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*bz = nil
+		return nil
+	case []byte:
+		*bz = bytes.Clone(v)
+		return nil
+	case string:
+		return bz.UnmarshalText([]byte(v))
+	default:
+		return fmt.Errorf("cannot scan %T into HexBytes", src)
+	}
+}
+
 // Bytes fulfills various interfaces in light-client, etc...
 func (bz HexBytes) Bytes() []byte {
 	return bz
 }
 
 func (bz HexBytes) String() string {
-	return strings.ToUpper(hex.EncodeToString(bz))
+	buf := make([]byte, hex.EncodedLen(len(bz)))
+	encodeUpperHex(buf, bz)
+	// buf is never modified afterwards.
+	return UnsafeString(buf)
 }
 
 // Format writes either address of 0th element in a slice in base 16 notation,
-// with leading 0x (%p), or casts HexBytes to bytes and writes as hexadecimal
-// string to s.
+// with leading 0x (%p), or hexadecimal digits to s, in lowercase for %x and in
+// uppercase for %X, %s and %v. The '#' flag adds a leading 0x, and a width
+// truncates the digits the same as ToHexString, e.g. "%8x" writes "1a2b3...".
+// %+v writes the multi-line Dump instead, which is easier to read in failed
+// test assertions.
 func (bz HexBytes) Format(s fmt.State, verb rune) {
+	formatHex(bz, s, verb, true, false)
+}
+
+// ParseHexBytes decodes hexadecimal digits in either case with an optional 0x prefix.
+func ParseHexBytes(s string) (HexBytes, error) {
+	var bz HexBytes
+	if err := bz.UnmarshalText([]byte(s)); err != nil {
+		return nil, err
+	}
+	return bz, nil
+}
+
+// encodeUpperHex encodes `src` into `dst` in uppercase hexadecimal digits.
+func encodeUpperHex(dst, src []byte) {
+	hex.Encode(dst, src)
+
+	// Ensure letter digits are capitalized.
+	for i := range dst[:hex.EncodedLen(len(src))] {
+		if dst[i] >= 'a' && dst[i] <= 'f' {
+			dst[i] = 'A' + (dst[i] - 'a')
+		}
+	}
+}
+
+// decodeHex decodes hexadecimal digits in either case with an optional 0x or 0X prefix.
+func decodeHex(text []byte) ([]byte, error) {
+	if len(text) >= 2 && text[0] == '0' && (text[1] == 'x' || text[1] == 'X') {
+		text = text[2:]
+	}
+
+	bz := make([]byte, hex.DecodedLen(len(text)))
+	if _, err := hex.Decode(bz, text); err != nil {
+		return nil, err
+	}
+	return bz, nil
+}
+
+// formatHex writes `bz` in hexadecimal digits to s, see HexBytes.Format. `upper` and `prefix` set
+// the case and whether to add a leading 0x for verbs other than %x and %X.
+func formatHex(bz []byte, s fmt.State, verb rune, upper, prefix bool) {
 	switch verb {
 	case 'p':
 		s.Write([]byte(fmt.Sprintf("%p", bz)))
-	default:
-		s.Write([]byte(fmt.Sprintf("%X", []byte(bz))))
+		return
+	case 'x':
+		upper = false
+	case 'X':
+		upper = true
+	case 'v':
+		if s.Flag('+') {
+			s.Write([]byte(Dump(bz)))
+			return
+		}
 	}
+
+	lim, _ := s.Width()
+	str := ToHexString(bz, lim)
+	if upper {
+		str = strings.ToUpper(str)
+	}
+	if prefix || s.Flag('#') {
+		str = "0x" + str
+	}
+	s.Write([]byte(str))
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// Hex0xBytes is the same as HexBytes except that it's encoded in lowercase hexadecimal digits
+// with 0x prefix, which is common in Ethereum-style tooling.
+type Hex0xBytes []byte
+
+var (
+	_ json.Marshaler           = Hex0xBytes{}
+	_ json.Unmarshaler         = &Hex0xBytes{}
+	_ encoding.TextMarshaler   = Hex0xBytes{}
+	_ encoding.TextUnmarshaler = &Hex0xBytes{}
+)
+
+// Marshal returns the raw bytes, the 0x-prefixed hexadecimal encoding only applies to JSON and text.
+func (bz Hex0xBytes) Marshal() ([]byte, error) {
+	return bz, nil
+}
+
+// Unmarshal sets the Hex0xBytes to the raw bytes `data` without decoding.
+func (bz *Hex0xBytes) Unmarshal(data []byte) error {
+	*bz = data
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface. The encoding is a JSON
+// quoted string of lowercase hexadecimal digits with 0x prefix.
+func (bz Hex0xBytes) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + bz.String() + `"`), nil
+}
+
+// UnmarshalJSON implements the json.Umarshaler interface.
+func (bz *Hex0xBytes) UnmarshalJSON(data []byte) error {
+	return (*HexBytes)(bz).UnmarshalJSON(data)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (bz Hex0xBytes) MarshalText() ([]byte, error) {
+	return []byte(bz.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface. It accepts hexadecimal digits
+// in either case with an optional 0x prefix.
+func (bz *Hex0xBytes) UnmarshalText(text []byte) error {
+	return (*HexBytes)(bz).UnmarshalText(text)
+}
+
+// Bytes returns the raw bytes of the Hex0xBytes.
+func (bz Hex0xBytes) Bytes() []byte {
+	return bz
+}
+
+func (bz Hex0xBytes) String() string {
+	buf := make([]byte, 2+hex.EncodedLen(len(bz)))
+	buf[0], buf[1] = '0', 'x'
+	hex.Encode(buf[2:], bz)
+	// buf is never modified afterwards.
+	return UnsafeString(buf)
+}
+
+// Format is the same as HexBytes.Format except that the digits are in lowercase for %s and %v,
+// and always have a leading 0x.
+func (bz Hex0xBytes) Format(s fmt.State, verb rune) {
+	formatHex(bz, s, verb, false, true)
 }
@@ -6,9 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 
 	. "github.com/daotl/guts/bytes"
 )
@@ -76,3 +79,150 @@ func TestHexBytes_String(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestHexBytesText(t *testing.T) {
+	req := require.New(t)
+
+	text, err := HexBytes("\x1a\x2b\x3c").MarshalText()
+	req.NoError(err)
+	req.Equal("1A2B3C", string(text))
+
+	var bz HexBytes
+	req.NoError(bz.UnmarshalText([]byte("1a2B3c")))
+	req.Equal(HexBytes("\x1a\x2b\x3c"), bz)
+	req.Error(bz.UnmarshalText([]byte("1A2")))
+	req.Error(bz.UnmarshalText([]byte("ZZ")))
+
+	// JSON and YAML map values of TextMarshaler types use the text encoding.
+	type Config struct {
+		Hashes map[string]HexBytes `yaml:"hashes"`
+	}
+	cfg := Config{Hashes: map[string]HexBytes{"genesis": HexBytes("\xab\xcd")}}
+
+	jsonBytes, err := json.Marshal(cfg)
+	req.NoError(err)
+	req.Equal(`{"Hashes":{"genesis":"ABCD"}}`, string(jsonBytes))
+
+	yamlBytes, err := yaml.Marshal(cfg)
+	req.NoError(err)
+	req.Equal("hashes:\n    genesis: ABCD\n", string(yamlBytes))
+	var cfg2 Config
+	req.NoError(yaml.Unmarshal([]byte("hashes:\n  genesis: abcd\n"), &cfg2))
+	req.Equal(cfg, cfg2)
+
+	// JSON map keys of fixed-size TextMarshaler types use the text encoding.
+	var h Hash32
+	h[0], h[31] = 0xab, 0xcd
+	names := map[Hash32]string{h: "genesis"}
+	jsonBytes, err = json.Marshal(names)
+	req.NoError(err)
+	req.Equal(`{"AB`+strings.Repeat("00", 30)+`CD":"genesis"}`, string(jsonBytes))
+	var names2 map[Hash32]string
+	req.NoError(json.Unmarshal(jsonBytes, &names2))
+	req.Equal(names, names2)
+}
+
+func TestHexBytesBinary(t *testing.T) {
+	req := require.New(t)
+
+	bz := HexBytes("\x01\x02")
+	data, err := bz.MarshalBinary()
+	req.NoError(err)
+	req.Equal([]byte("\x01\x02"), data)
+	data[0] = 0xff
+	req.Equal(HexBytes("\x01\x02"), bz)
+
+	var bz2 HexBytes
+	req.NoError(bz2.UnmarshalBinary(data))
+	req.Equal(HexBytes("\xff\x02"), bz2)
+	data[1] = 0xff
+	req.Equal(HexBytes("\xff\x02"), bz2)
+}
+
+func TestHexBytesSQL(t *testing.T) {
+	req := require.New(t)
+
+	v, err := HexBytes("\x01\x02").Value()
+	req.NoError(err)
+	req.Equal([]byte("\x01\x02"), v)
+	v, err = HexBytes(nil).Value()
+	req.NoError(err)
+	req.Nil(v)
+
+	var bz HexBytes
+	req.NoError(bz.Scan([]byte("\x0a\x0b")))
+	req.Equal(HexBytes("\x0a\x0b"), bz)
+	req.NoError(bz.Scan("0C0d"))
+	req.Equal(HexBytes("\x0c\x0d"), bz)
+	req.NoError(bz.Scan(nil))
+	req.Nil(bz)
+	req.Error(bz.Scan(42))
+}
+
+func TestHexBytesLenientDecoding(t *testing.T) {
+	req := require.New(t)
+
+	for _, s := range []string{"1a2b3c", "1A2B3C", "0x1a2B3c", "0X1A2B3C"} {
+		bz, err := ParseHexBytes(s)
+		req.NoError(err, s)
+		req.Equal(HexBytes("\x1a\x2b\x3c"), bz, s)
+
+		var bz2 HexBytes
+		req.NoError(json.Unmarshal([]byte(`"`+s+`"`), &bz2), s)
+		req.Equal(bz, bz2, s)
+	}
+
+	bz, err := ParseHexBytes("0x")
+	req.NoError(err)
+	req.Empty(bz)
+
+	for _, s := range []string{"0x1", "x12", "0xzz", "00x1"} {
+		_, err := ParseHexBytes(s)
+		req.Error(err, s)
+	}
+}
+
+func TestHex0xBytes(t *testing.T) {
+	req := require.New(t)
+
+	type TestStruct struct {
+		H Hex0xBytes
+	}
+
+	jsonBytes, err := json.Marshal(TestStruct{H: Hex0xBytes("\x1a\x2b\x3c")})
+	req.NoError(err)
+	req.Equal(`{"H":"0x1a2b3c"}`, string(jsonBytes))
+
+	var ts TestStruct
+	req.NoError(json.Unmarshal(jsonBytes, &ts))
+	req.Equal(Hex0xBytes("\x1a\x2b\x3c"), ts.H)
+	req.NoError(json.Unmarshal([]byte(`{"H":"1A2B"}`), &ts))
+	req.Equal(Hex0xBytes("\x1a\x2b"), ts.H)
+
+	text, err := Hex0xBytes{}.MarshalText()
+	req.NoError(err)
+	req.Equal("0x", string(text))
+	req.NoError(ts.H.UnmarshalText(text))
+	req.Empty(ts.H)
+}
+
+func TestHexBytesFormat(t *testing.T) {
+	assr := assert.New(t)
+
+	bz := HexBytes("\x1a\x2b\x3c\x4d\x5e\x6f")
+	assr.Equal("1A2B3C4D5E6F", fmt.Sprintf("%v", bz))
+	assr.Equal("1A2B3C4D5E6F", fmt.Sprintf("%s", bz))
+	assr.Equal("1a2b3c4d5e6f", fmt.Sprintf("%x", bz))
+	assr.Equal("1A2B3C4D5E6F", fmt.Sprintf("%X", bz))
+	assr.Equal("0x1a2b3c4d5e6f", fmt.Sprintf("%#x", bz))
+	assr.Equal("1a2b3...", fmt.Sprintf("%8x", bz))
+	assr.Equal("1A2B3...", fmt.Sprintf("%8v", bz))
+	assr.Equal("1a2b3c4d5e6f", fmt.Sprintf("%12x", bz))
+	assr.Equal("1a2b3c4d5e6f", fmt.Sprintf("%20x", bz))
+
+	h0x := Hex0xBytes(bz)
+	assr.Equal("0x1a2b3c4d5e6f", fmt.Sprintf("%v", h0x))
+	assr.Equal("0x1A2B3C4D5E6F", fmt.Sprintf("%X", h0x))
+	assr.Equal("0x1a2b3...", fmt.Sprintf("%8s", h0x))
+	assr.Equal("0x1a2b3c4d5e6f", h0x.String())
+}
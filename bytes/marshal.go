@@ -0,0 +1,578 @@
+package bytes
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ErrTrailingBytes is returned by Unmarshal if there are bytes left after decoding the value.
+var ErrTrailingBytes = errors.New("trailing bytes")
+
+// BinaryAppender is implemented by types that append their own binary encoding, e.g. with
+// hand-written or generated code, Marshal uses it instead of reflection as a fast path.
+type BinaryAppender interface {
+	AppendBinary(dst []byte) ([]byte, error)
+}
+
+// BinaryDecoder is implemented by types that decode their own binary encoding from the beginning
+// of `b` and return the remaining bytes, Unmarshal uses it instead of reflection as a fast path.
+type BinaryDecoder interface {
+	DecodeBinary(b []byte) ([]byte, error)
+}
+
+var (
+	binaryAppenderType = reflect.TypeOf((*BinaryAppender)(nil)).Elem()
+	binaryDecoderType  = reflect.TypeOf((*BinaryDecoder)(nil)).Elem()
+)
+
+// FieldError is returned by Marshal and Unmarshal with the path of the field that failed, e.g.
+// "Header.Items[2].Len".
+type FieldError struct {
+	Path string
+	Err  error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("field %s: %v", e.Path, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// withPath prefixes the path of `err` with `name`, which is either a field name or an index like
+// "[2]".
+func withPath(err error, name string) error {
+	var fe *FieldError
+	if errors.As(err, &fe) {
+		if strings.HasPrefix(fe.Path, "[") {
+			fe.Path = name + fe.Path
+		} else {
+			fe.Path = name + "." + fe.Path
+		}
+		return fe
+	}
+	return &FieldError{Path: name, Err: err}
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// binOpts is the encoding options of a value parsed from the `bin` struct tag.
+type binOpts struct {
+	codec   Codec
+	varint  bool
+	lenKind string
+}
+
+// fieldInfo describes how to encode a struct field.
+type fieldInfo struct {
+	index    int
+	name     string
+	hasOrder bool
+	opts     binOpts
+}
+
+// structFieldsCache caches []fieldInfo of struct types.
+var structFieldsCache sync.Map
+
+// structFields returns the encoded fields of the struct type `t` parsed from the `bin` tags.
+func structFields(t reflect.Type) ([]fieldInfo, error) {
+	if fields, ok := structFieldsCache.Load(t); ok {
+		return fields.([]fieldInfo), nil
+	}
+
+	fields := make([]fieldInfo, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("bin")
+		if tag == "-" || (!sf.IsExported() && !isEncodedEmbedded(sf)) {
+			continue
+		}
+
+		f := fieldInfo{index: i, name: sf.Name}
+		for _, opt := range strings.Split(tag, ",") {
+			switch opt {
+			case "":
+			case "le":
+				f.hasOrder, f.opts.codec = true, LittleEndian
+			case "be":
+				f.hasOrder, f.opts.codec = true, BigEndian
+			case "varint":
+				f.opts.varint = true
+			case "len=u8", "len=u16", "len=u32", "len=u64", "len=uvarint":
+				f.opts.lenKind = strings.TrimPrefix(opt, "len=")
+			default:
+				return nil, fmt.Errorf("%s.%s: invalid bin tag option %q", t, sf.Name, opt)
+			}
+		}
+		fields = append(fields, f)
+	}
+
+	structFieldsCache.Store(t, fields)
+	return fields, nil
+}
+
+// isEncodedEmbedded reports whether the unexported field `sf` is an embedded struct whose exported
+// fields are encoded. Other unexported embedded types are skipped like unexported fields, since
+// reflection can't set them or call their methods.
+func isEncodedEmbedded(sf reflect.StructField) bool {
+	pt := reflect.PointerTo(sf.Type)
+	return sf.Anonymous && sf.Type.Kind() == reflect.Struct &&
+		!pt.Implements(binaryAppenderType) && !pt.Implements(binaryDecoderType)
+}
+
+// emptyTypeCache caches whether types are encoded as no bytes.
+var emptyTypeCache sync.Map
+
+// encodesEmpty reports whether values of type `t` are always encoded as no bytes, e.g. struct{},
+// [0]int or structs with only skipped fields. Slices of such types are not supported since their
+// length can't be checked against the remaining bytes when decoding.
+func encodesEmpty(t reflect.Type) bool {
+	if empty, ok := emptyTypeCache.Load(t); ok {
+		return empty.(bool)
+	}
+
+	empty := false
+	pt := reflect.PointerTo(t)
+	if !pt.Implements(binaryAppenderType) && !pt.Implements(binaryDecoderType) {
+		switch t.Kind() {
+		case reflect.Array:
+			empty = t.Len() == 0 || encodesEmpty(t.Elem())
+		case reflect.Struct:
+			fields, err := structFields(t)
+			empty = err == nil
+			for _, f := range fields {
+				if !encodesEmpty(t.Field(f.index).Type) {
+					empty = false
+					break
+				}
+			}
+		}
+	}
+
+	emptyTypeCache.Store(t, empty)
+	return empty
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// Marshal encodes a fixed-layout value, usually a struct, into bytes using reflection.
+//
+// Integers and floats are encoded in fixed width and little endian by default, int and uint are
+// encoded as 64-bit integers, bools as a single 0 or 1 byte. Strings and slices are prefixed with
+// their length as an unsigned varint by default, arrays and structs are encoded element by element
+// without prefix. Pointers, maps, interfaces, channels and functions are not supported, neither are
+// slices of elements encoded as no bytes, e.g. []struct{}. Unexported fields are skipped, except
+// for embedded structs whose exported fields are encoded.
+//
+// The encoding of a struct field can be customized with comma-separated options in the `bin` tag:
+//   - "-": skip the field.
+//   - "le" or "be": the byte order of the field, which is also the default for the fields of a
+//     nested struct.
+//   - "varint": encode integers wider than 8 bits as unsigned or zigzag varints.
+//   - "len=u8", "len=u16", "len=u32", "len=u64" or "len=uvarint": the type of the length prefix of
+//     strings and slices.
+//
+// Values implementing BinaryAppender encode themselves, including with pointer receivers. `v` may
+// be a pointer to the value to encode.
+func Marshal(v any) ([]byte, error) {
+	return AppendMarshal(nil, v)
+}
+
+// AppendMarshal appends the encoding of `v` as Marshal to `dst` and returns the extended buffer.
+func AppendMarshal(dst []byte, v any) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, errors.New("cannot marshal nil")
+		}
+		rv = rv.Elem()
+	} else if rv.IsValid() {
+		// Make the value addressable, so that the nested values use AppendBinary with pointer
+		// receivers consistently, like Unmarshal uses DecodeBinary.
+		addressable := reflect.New(rv.Type()).Elem()
+		addressable.Set(rv)
+		rv = addressable
+	}
+	return appendValue(dst, rv, binOpts{codec: LittleEndian})
+}
+
+func appendValue(dst []byte, v reflect.Value, opts binOpts) ([]byte, error) {
+	if !v.IsValid() {
+		return nil, errors.New("cannot marshal nil")
+	}
+	if v.Type().Implements(binaryAppenderType) {
+		return v.Interface().(BinaryAppender).AppendBinary(dst)
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(binaryAppenderType) {
+		return v.Addr().Interface().(BinaryAppender).AppendBinary(dst)
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(dst, 1), nil
+		}
+		return append(dst, 0), nil
+	case reflect.Int8:
+		return append(dst, byte(v.Int())), nil
+	case reflect.Uint8:
+		return append(dst, byte(v.Uint())), nil
+	case reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int:
+		if opts.varint {
+			return AppendVarint(dst, v.Int()), nil
+		}
+		return appendFixedUint(dst, uint64(v.Int()), v.Kind(), opts.codec), nil
+	case reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint, reflect.Uintptr:
+		if opts.varint {
+			return AppendUvarint(dst, v.Uint()), nil
+		}
+		return appendFixedUint(dst, v.Uint(), v.Kind(), opts.codec), nil
+	case reflect.Float32:
+		return opts.codec.AppendU32(dst, math.Float32bits(float32(v.Float()))), nil
+	case reflect.Float64:
+		return opts.codec.AppendU64(dst, math.Float64bits(v.Float())), nil
+	case reflect.String:
+		dst, err := appendLen(dst, v.Len(), opts)
+		if err != nil {
+			return nil, err
+		}
+		return append(dst, v.String()...), nil
+	case reflect.Slice:
+		if encodesEmpty(v.Type().Elem()) {
+			return nil, fmt.Errorf("unsupported type %s of elements encoded as no bytes", v.Type())
+		}
+		dst, err := appendLen(dst, v.Len(), opts)
+		if err != nil {
+			return nil, err
+		}
+		return appendElems(dst, v, opts)
+	case reflect.Array:
+		return appendElems(dst, v, opts)
+	case reflect.Struct:
+		fields, err := structFields(v.Type())
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range fields {
+			fopts := f.opts
+			if !f.hasOrder {
+				fopts.codec = opts.codec
+			}
+			if dst, err = appendValue(dst, v.Field(f.index), fopts); err != nil {
+				return nil, withPath(err, f.name)
+			}
+		}
+		return dst, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", v.Type())
+	}
+}
+
+// fixedSize returns the encoded size of integers of kind `k`, int, uint and uintptr are always 8
+// bytes regardless of the platform.
+func fixedSize(k reflect.Kind) int {
+	switch k {
+	case reflect.Int16, reflect.Uint16:
+		return 2
+	case reflect.Int32, reflect.Uint32:
+		return 4
+	default:
+		return 8
+	}
+}
+
+// appendFixedUint appends the lower fixedSize(k) bytes of `val`.
+func appendFixedUint(dst []byte, val uint64, k reflect.Kind, c Codec) []byte {
+	switch fixedSize(k) {
+	case 2:
+		return c.AppendU16(dst, uint16(val))
+	case 4:
+		return c.AppendU32(dst, uint32(val))
+	default:
+		return c.AppendU64(dst, val)
+	}
+}
+
+// appendLen appends the length prefix `n`, returns an error if it overflows the prefix type.
+func appendLen(dst []byte, n int, opts binOpts) ([]byte, error) {
+	var limit uint64
+	switch opts.lenKind {
+	case "u8":
+		limit = math.MaxUint8
+	case "u16":
+		limit = math.MaxUint16
+	case "u32":
+		limit = math.MaxUint32
+	}
+	if limit > 0 && uint64(n) > limit {
+		return nil, fmt.Errorf("length %d overflows the %s length prefix", n, opts.lenKind)
+	}
+
+	switch opts.lenKind {
+	case "u8":
+		return append(dst, uint8(n)), nil
+	case "u16":
+		return opts.codec.AppendU16(dst, uint16(n)), nil
+	case "u32":
+		return opts.codec.AppendU32(dst, uint32(n)), nil
+	case "u64":
+		return opts.codec.AppendU64(dst, uint64(n)), nil
+	default:
+		return AppendUvarint(dst, uint64(n)), nil
+	}
+}
+
+func appendElems(dst []byte, v reflect.Value, opts binOpts) ([]byte, error) {
+	// Bytes implementing BinaryAppender with either receiver encode themselves, consistently with
+	// decodeElems.
+	if v.Type().Elem().Kind() == reflect.Uint8 &&
+		!reflect.PointerTo(v.Type().Elem()).Implements(binaryAppenderType) {
+		if v.Kind() == reflect.Slice {
+			return append(dst, v.Bytes()...), nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			dst = append(dst, byte(v.Index(i).Uint()))
+		}
+		return dst, nil
+	}
+
+	// The length prefix option only applies to the outermost slice.
+	opts.lenKind = ""
+	var err error
+	for i := 0; i < v.Len(); i++ {
+		if dst, err = appendValue(dst, v.Index(i), opts); err != nil {
+			return nil, withPath(err, fmt.Sprintf("[%d]", i))
+		}
+	}
+	return dst, nil
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// Unmarshal decodes bytes encoded by Marshal into the value pointed to by `v`, returns
+// ErrShortBuffer wrapped in a FieldError if `b` is truncated, or ErrTrailingBytes if there are
+// bytes left.
+//
+// Values implementing BinaryDecoder decode themselves.
+func Unmarshal(b []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("cannot unmarshal into %T, a non-nil pointer is required", v)
+	}
+
+	rest, err := decodeValue(b, rv.Elem(), binOpts{codec: LittleEndian})
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return fmt.Errorf("%w: %d bytes", ErrTrailingBytes, len(rest))
+	}
+	return nil
+}
+
+func decodeValue(b []byte, v reflect.Value, opts binOpts) ([]byte, error) {
+	if v.CanAddr() && v.Addr().Type().Implements(binaryDecoderType) {
+		return v.Addr().Interface().(BinaryDecoder).DecodeBinary(b)
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		val, rest, err := ReadU08(b)
+		if err != nil {
+			return nil, err
+		}
+		if val > 1 {
+			return nil, fmt.Errorf("invalid bool value %d", val)
+		}
+		v.SetBool(val == 1)
+		return rest, nil
+	case reflect.Int8:
+		val, rest, err := ReadI08(b)
+		if err != nil {
+			return nil, err
+		}
+		v.SetInt(int64(val))
+		return rest, nil
+	case reflect.Uint8:
+		val, rest, err := ReadU08(b)
+		if err != nil {
+			return nil, err
+		}
+		v.SetUint(uint64(val))
+		return rest, nil
+	case reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int:
+		var val int64
+		var rest []byte
+		var err error
+		if opts.varint {
+			val, rest, err = ReadVarint(b)
+		} else {
+			var u uint64
+			u, rest, err = readFixedUint(b, v.Kind(), opts.codec)
+			// Sign-extend 16-bit and 32-bit integers.
+			switch fixedSize(v.Kind()) {
+			case 2:
+				val = int64(int16(u))
+			case 4:
+				val = int64(int32(u))
+			default:
+				val = int64(u)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		if v.OverflowInt(val) {
+			return nil, fmt.Errorf("value %d overflows %s", val, v.Type())
+		}
+		v.SetInt(val)
+		return rest, nil
+	case reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint, reflect.Uintptr:
+		var val uint64
+		var rest []byte
+		var err error
+		if opts.varint {
+			val, rest, err = ReadUvarint(b)
+		} else {
+			val, rest, err = readFixedUint(b, v.Kind(), opts.codec)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if v.OverflowUint(val) {
+			return nil, fmt.Errorf("value %d overflows %s", val, v.Type())
+		}
+		v.SetUint(val)
+		return rest, nil
+	case reflect.Float32:
+		val, rest, err := opts.codec.ReadU32(b)
+		if err != nil {
+			return nil, err
+		}
+		v.SetFloat(float64(math.Float32frombits(val)))
+		return rest, nil
+	case reflect.Float64:
+		val, rest, err := opts.codec.ReadU64(b)
+		if err != nil {
+			return nil, err
+		}
+		v.SetFloat(math.Float64frombits(val))
+		return rest, nil
+	case reflect.String:
+		n, rest, err := readLen(b, opts)
+		if err != nil {
+			return nil, err
+		}
+		v.SetString(string(rest[:n]))
+		return rest[n:], nil
+	case reflect.Slice:
+		if encodesEmpty(v.Type().Elem()) {
+			return nil, fmt.Errorf("unsupported type %s of elements encoded as no bytes", v.Type())
+		}
+		n, rest, err := readLen(b, opts)
+		if err != nil {
+			return nil, err
+		}
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+		return decodeElems(rest, v, opts)
+	case reflect.Array:
+		return decodeElems(b, v, opts)
+	case reflect.Struct:
+		fields, err := structFields(v.Type())
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range fields {
+			fopts := f.opts
+			if !f.hasOrder {
+				fopts.codec = opts.codec
+			}
+			if b, err = decodeValue(b, v.Field(f.index), fopts); err != nil {
+				return nil, withPath(err, f.name)
+			}
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", v.Type())
+	}
+}
+
+// readFixedUint reads an unsigned integer of fixedSize(k) bytes.
+func readFixedUint(b []byte, k reflect.Kind, c Codec) (uint64, []byte, error) {
+	switch fixedSize(k) {
+	case 2:
+		val, rest, err := c.ReadU16(b)
+		return uint64(val), rest, err
+	case 4:
+		val, rest, err := c.ReadU32(b)
+		return uint64(val), rest, err
+	default:
+		return c.ReadU64(b)
+	}
+}
+
+// readLen reads a length prefix and ensures there are at least as many bytes left, since every
+// element takes at least one byte, slices of elements encoded as no bytes are not supported.
+func readLen(b []byte, opts binOpts) (int, []byte, error) {
+	var n uint64
+	var rest []byte
+	var err error
+	switch opts.lenKind {
+	case "u8":
+		var val uint8
+		val, rest, err = ReadU08(b)
+		n = uint64(val)
+	case "u16":
+		var val uint16
+		val, rest, err = opts.codec.ReadU16(b)
+		n = uint64(val)
+	case "u32":
+		var val uint32
+		val, rest, err = opts.codec.ReadU32(b)
+		n = uint64(val)
+	case "u64":
+		n, rest, err = opts.codec.ReadU64(b)
+	default:
+		n, rest, err = ReadUvarint(b)
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	if n > uint64(len(rest)) {
+		return 0, nil, fmt.Errorf("%w: length %d exceeds the remaining %d bytes", ErrShortBuffer, n,
+			len(rest))
+	}
+	return int(n), rest, nil
+}
+
+func decodeElems(b []byte, v reflect.Value, opts binOpts) ([]byte, error) {
+	if v.Type().Elem().Kind() == reflect.Uint8 &&
+		!reflect.PointerTo(v.Type().Elem()).Implements(binaryDecoderType) {
+		if len(b) < v.Len() {
+			return nil, shortBuffer(v.Len(), len(b))
+		}
+		if v.Kind() == reflect.Slice {
+			copy(v.Bytes(), b)
+		} else {
+			for i := 0; i < v.Len(); i++ {
+				v.Index(i).SetUint(uint64(b[i]))
+			}
+		}
+		return b[v.Len():], nil
+	}
+
+	// The length prefix option only applies to the outermost slice.
+	opts.lenKind = ""
+	var err error
+	for i := 0; i < v.Len(); i++ {
+		if b, err = decodeValue(b, v.Index(i), opts); err != nil {
+			return nil, withPath(err, fmt.Sprintf("[%d]", i))
+		}
+	}
+	return b, nil
+}
@@ -0,0 +1,320 @@
+package bytes_test
+
+import (
+	"encoding/hex"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/daotl/guts/bytes"
+)
+
+type testMarshalItem struct {
+	ID  uint16 `bin:"be"`
+	Val int64  `bin:"varint"`
+}
+
+type testMarshalHeader struct {
+	Magic   [2]byte
+	Version uint8
+	Flags   uint32
+	Neg     int16
+	Ok      bool
+	Ratio   float32
+	Name    string `bin:"len=u8"`
+	Data    []byte `bin:"len=u16,be"`
+	Items   []testMarshalItem
+	Counts  []uint64              `bin:"varint"`
+	Inner   struct{ A, B uint16 } `bin:"be"`
+	Skipped int                   `bin:"-"`
+	private int
+}
+
+// testAppender implements BinaryAppender and BinaryDecoder as a single byte.
+type testAppender struct {
+	v byte
+}
+
+func (a testAppender) AppendBinary(dst []byte) ([]byte, error) {
+	return append(dst, a.v^0xff), nil
+}
+
+func (a *testAppender) DecodeBinary(b []byte) ([]byte, error) {
+	val, rest, err := ReadU08(b)
+	a.v = val ^ 0xff
+	return rest, err
+}
+
+func TestMarshalStruct(t *testing.T) {
+	req := require.New(t)
+
+	h := testMarshalHeader{
+		Magic:   [2]byte{'g', 't'},
+		Version: 1,
+		Flags:   0x01020304,
+		Neg:     -2,
+		Ok:      true,
+		Ratio:   1,
+		Name:    "ab",
+		Data:    []byte{0xaa, 0xbb},
+		Items:   []testMarshalItem{{ID: 0x0102, Val: -1}},
+		Counts:  []uint64{1, 300},
+		Skipped: 1,
+	}
+	h.Inner.A, h.Inner.B = 1, 2
+
+	bz, err := Marshal(h)
+	req.NoError(err)
+	req.Equal("6774"+"01"+"04030201"+"feff"+"01"+"0000803f"+"026162"+"0002aabb"+
+		"01"+"010201"+"02"+"01ac02"+"00010002", hex.EncodeToString(bz))
+
+	var h2 testMarshalHeader
+	req.NoError(Unmarshal(bz, &h2))
+	h.Skipped = 0
+	req.Equal(h, h2)
+
+	// Appending to an existing buffer.
+	bz2, err := AppendMarshal([]byte{0xff}, h)
+	req.NoError(err)
+	req.Equal(append([]byte{0xff}, bz...), bz2)
+}
+
+func TestMarshalPlatformInts(t *testing.T) {
+	req := require.New(t)
+
+	// int, uint and uintptr are 64-bit regardless of the platform.
+	type TestStruct struct {
+		A int
+		B uint
+		C uintptr
+	}
+
+	ts := TestStruct{A: -1, B: 2, C: 3}
+	bz, err := Marshal(ts)
+	req.NoError(err)
+	req.Equal("ffffffffffffffff"+"0200000000000000"+"0300000000000000", hex.EncodeToString(bz))
+
+	var ts2 TestStruct
+	req.NoError(Unmarshal(bz, &ts2))
+	req.Equal(ts, ts2)
+
+	if strconv.IntSize == 32 {
+		bz[15] = 1
+		req.Error(Unmarshal(bz, &ts2))
+	}
+}
+
+// testPtrAppender implements BinaryAppender and BinaryDecoder with pointer receivers as a single
+// byte.
+type testPtrAppender struct {
+	v byte
+}
+
+func (a *testPtrAppender) AppendBinary(dst []byte) ([]byte, error) {
+	return append(dst, a.v^0xff), nil
+}
+
+func (a *testPtrAppender) DecodeBinary(b []byte) ([]byte, error) {
+	val, rest, err := ReadU08(b)
+	a.v = val ^ 0xff
+	return rest, err
+}
+
+// testPtrByte implements BinaryAppender and BinaryDecoder with pointer receivers as a single
+// byte.
+type testPtrByte uint8
+
+func (a *testPtrByte) AppendBinary(dst []byte) ([]byte, error) {
+	return append(dst, byte(*a)^0xff), nil
+}
+
+func (a *testPtrByte) DecodeBinary(b []byte) ([]byte, error) {
+	val, rest, err := ReadU08(b)
+	*a = testPtrByte(val ^ 0xff)
+	return rest, err
+}
+
+func TestMarshalFastPath(t *testing.T) {
+	req := require.New(t)
+
+	type TestStruct struct {
+		A testAppender
+		L []testAppender
+	}
+
+	ts := TestStruct{A: testAppender{0x01}, L: []testAppender{{0x02}}}
+	bz, err := Marshal(ts)
+	req.NoError(err)
+	req.Equal([]byte{0xfe, 0x01, 0xfd}, bz)
+
+	var ts2 TestStruct
+	req.NoError(Unmarshal(bz, &ts2))
+	req.Equal(ts, ts2)
+
+	// Pointer receivers are used for top-level values, fields and elements alike.
+	type PtrStruct struct {
+		A testPtrAppender
+		L []testPtrAppender
+		R [1]testPtrAppender
+	}
+
+	ps := PtrStruct{A: testPtrAppender{0x01}, L: []testPtrAppender{{0x02}}, R: [1]testPtrAppender{{0x03}}}
+	for _, v := range []any{ps, &ps} {
+		bz, err = Marshal(v)
+		req.NoError(err)
+		req.Equal([]byte{0xfe, 0x01, 0xfd, 0xfc}, bz)
+		var ps2 PtrStruct
+		req.NoError(Unmarshal(bz, &ps2))
+		req.Equal(ps, ps2)
+	}
+
+	// Byte elements with pointer receivers don't take the raw bytes path.
+	type ByteStruct struct {
+		A [2]testPtrByte
+		L []testPtrByte
+	}
+
+	bs := ByteStruct{A: [2]testPtrByte{0x01, 0x02}, L: []testPtrByte{0x03}}
+	bz, err = Marshal(bs)
+	req.NoError(err)
+	req.Equal([]byte{0xfe, 0xfd, 0x01, 0xfc}, bz)
+	var bs2 ByteStruct
+	req.NoError(Unmarshal(bz, &bs2))
+	req.Equal(bs, bs2)
+
+	bz, err = Marshal(testPtrAppender{0x01})
+	req.NoError(err)
+	req.Equal([]byte{0xfe}, bz)
+	var pa testPtrAppender
+	req.NoError(Unmarshal(bz, &pa))
+	req.Equal(testPtrAppender{0x01}, pa)
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	assr := assert.New(t)
+
+	type TestStruct struct {
+		Items []testMarshalItem
+	}
+
+	bz, err := Marshal(TestStruct{Items: []testMarshalItem{{1, 1}, {2, 2}, {3, 3}}})
+	assr.NoError(err)
+
+	// Truncated in the middle of Items[2].ID.
+	var ts TestStruct
+	err = Unmarshal(bz[:len(bz)-2], &ts)
+	assr.ErrorIs(err, ErrShortBuffer)
+	var fe *FieldError
+	assr.ErrorAs(err, &fe)
+	assr.Equal("Items[2].ID", fe.Path)
+
+	err = Unmarshal(bz[:len(bz)-1], &ts)
+	assr.ErrorAs(err, &fe)
+	assr.Equal("Items[2].Val", fe.Path)
+
+	// The length prefix exceeds the input.
+	err = Unmarshal([]byte{0x05, 0x00}, &ts)
+	assr.ErrorIs(err, ErrShortBuffer)
+	assr.ErrorAs(err, &fe)
+	assr.Equal("Items", fe.Path)
+
+	assr.ErrorIs(Unmarshal(append(bz, 0), &ts), ErrTrailingBytes)
+
+	var b bool
+	assr.Error(Unmarshal([]byte{2}, &b))
+	assr.Error(Unmarshal([]byte{0}, b))
+	assr.Error(Unmarshal([]byte{0}, nil))
+
+	var i8 struct {
+		V int8 `bin:"varint"`
+	}
+	// 8-bit integers are always a single byte.
+	assr.NoError(Unmarshal([]byte{0x01}, &i8))
+	assr.Equal(int8(1), i8.V)
+
+	var i16 struct {
+		V int16 `bin:"varint"`
+	}
+	err = Unmarshal(AppendVarint(nil, 1<<20), &i16)
+	assr.ErrorAs(err, &fe)
+	assr.Equal("V", fe.Path)
+}
+
+func TestMarshalErrors(t *testing.T) {
+	assr := assert.New(t)
+
+	_, err := Marshal(nil)
+	assr.Error(err)
+	_, err = Marshal(struct{ P *int }{})
+	assr.Error(err)
+	_, err = Marshal(struct {
+		V int `bin:"bad"`
+	}{})
+	assr.Error(err)
+	_, err = Marshal((*testMarshalItem)(nil))
+	assr.Error(err)
+
+	// Slices of elements encoded as no bytes.
+	_, err = Marshal([]struct{}{{}, {}, {}})
+	assr.Error(err)
+	_, err = Marshal(struct {
+		L []struct {
+			A int `bin:"-"`
+		}
+	}{})
+	assr.Error(err)
+	assr.Error(Unmarshal([]byte{3}, &[]struct{}{}))
+	// Arrays of them are fine.
+	bz, err := Marshal(struct {
+		A [3]struct{}
+		B uint8
+	}{B: 1})
+	assr.NoError(err)
+	assr.Equal([]byte{1}, bz)
+
+	// The length overflows the prefix type.
+	_, err = Marshal(struct {
+		S []byte `bin:"len=u8"`
+	}{S: make([]byte, 300)})
+	var fe *FieldError
+	assr.ErrorAs(err, &fe)
+	assr.Equal("S", fe.Path)
+	_, err = Marshal(struct {
+		S string `bin:"len=u16"`
+	}{S: string(make([]byte, 1<<16))})
+	assr.Error(err)
+	_, err = Marshal(struct {
+		S string `bin:"len=u16"`
+	}{S: string(make([]byte, 1<<16-1))})
+	assr.NoError(err)
+}
+
+type testMarshalInt uint16
+
+type testMarshalEmbedded struct {
+	A uint8
+}
+
+func TestMarshalEmbedded(t *testing.T) {
+	req := require.New(t)
+
+	// Unexported embedded non-struct types are skipped, the exported fields of unexported embedded
+	// structs are encoded.
+	type TestStruct struct {
+		testMarshalInt
+		testMarshalEmbedded
+		B uint8
+	}
+
+	ts := TestStruct{testMarshalInt: 1, testMarshalEmbedded: testMarshalEmbedded{A: 2}, B: 3}
+	bz, err := Marshal(ts)
+	req.NoError(err)
+	req.Equal([]byte{2, 3}, bz)
+
+	var ts2 TestStruct
+	req.NoError(Unmarshal(bz, &ts2))
+	ts.testMarshalInt = 0
+	req.Equal(ts, ts2)
+}
@@ -2,6 +2,13 @@
 
 package bytes
 
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"math/bits"
+	"unsafe"
+)
+
 // Concat an array of bytes.
 func Concat(src ...[]byte) []byte {
 	siz := 0
@@ -17,3 +24,237 @@ func Concat(src ...[]byte) []byte {
 
 	return buf
 }
+
+// ConcatInto concatenates an array of bytes into `dst`, reusing its underlying array if it has
+// enough capacity, e.g. a buffer got from Pool, and returns the result. If any of `src` shares
+// memory with the underlying array of `dst`, e.g. ConcatInto(buf, hdr, buf), a new array is
+// allocated so that the inputs are not overwritten before being copied.
+func ConcatInto(dst []byte, src ...[]byte) []byte {
+	siz := 0
+	for _, raw := range src {
+		siz += len(raw)
+	}
+
+	if cap(dst) < siz || overlapsAny(dst[:cap(dst)], src) {
+		dst = make([]byte, siz)
+	}
+	buf := dst[:siz]
+	pos := 0
+	for _, bin := range src {
+		pos += copy(buf[pos:], bin)
+	}
+
+	return buf
+}
+
+// overlapsAny reports whether any of `src` shares memory with `a`.
+func overlapsAny(a []byte, src [][]byte) bool {
+	if len(a) == 0 {
+		return false
+	}
+	start := uintptr(unsafe.Pointer(unsafe.SliceData(a)))
+	end := start + uintptr(len(a))
+	for _, b := range src {
+		if len(b) == 0 {
+			continue
+		}
+		bStart := uintptr(unsafe.Pointer(unsafe.SliceData(b)))
+		if bStart < end && start < bStart+uintptr(len(b)) {
+			return true
+		}
+	}
+	return false
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// Xor returns the bitwise XOR of `a` and `b` of the length of the shorter one.
+func Xor(a, b []byte) []byte {
+	dst := make([]byte, min(len(a), len(b)))
+	XorInto(dst, a, b)
+	return dst
+}
+
+// XorInto sets dst[i] = a[i] ^ b[i] for i < n = min(len(a), len(b)) and returns n, panics if `dst`
+// is shorter than n. `dst` may be the same slice as `a` or `b` for in-place operation.
+func XorInto(dst, a, b []byte) int {
+	// subtle.XORBytes is vectorized on most architectures.
+	return subtle.XORBytes(dst, a, b)
+}
+
+// And returns the bitwise AND of `a` and `b` of the length of the shorter one.
+func And(a, b []byte) []byte {
+	dst := make([]byte, min(len(a), len(b)))
+	AndInto(dst, a, b)
+	return dst
+}
+
+// AndInto sets dst[i] = a[i] & b[i] for i < n = min(len(a), len(b)) and returns n, panics if `dst`
+// is shorter than n. `dst` may be the same slice as `a` or `b` for in-place operation.
+func AndInto(dst, a, b []byte) int {
+	n := min(len(a), len(b))
+	dst, a, b = dst[:n], a[:n], b[:n]
+	i := 0
+	for ; i+8 <= n; i += 8 {
+		binary.LittleEndian.PutUint64(dst[i:],
+			binary.LittleEndian.Uint64(a[i:])&binary.LittleEndian.Uint64(b[i:]))
+	}
+	for ; i < n; i++ {
+		dst[i] = a[i] & b[i]
+	}
+	return n
+}
+
+// Or returns the bitwise OR of `a` and `b` of the length of the shorter one.
+func Or(a, b []byte) []byte {
+	dst := make([]byte, min(len(a), len(b)))
+	OrInto(dst, a, b)
+	return dst
+}
+
+// OrInto sets dst[i] = a[i] | b[i] for i < n = min(len(a), len(b)) and returns n, panics if `dst`
+// is shorter than n. `dst` may be the same slice as `a` or `b` for in-place operation.
+func OrInto(dst, a, b []byte) int {
+	n := min(len(a), len(b))
+	dst, a, b = dst[:n], a[:n], b[:n]
+	i := 0
+	for ; i+8 <= n; i += 8 {
+		binary.LittleEndian.PutUint64(dst[i:],
+			binary.LittleEndian.Uint64(a[i:])|binary.LittleEndian.Uint64(b[i:]))
+	}
+	for ; i < n; i++ {
+		dst[i] = a[i] | b[i]
+	}
+	return n
+}
+
+// Not returns the bitwise NOT of `a`.
+func Not(a []byte) []byte {
+	dst := make([]byte, len(a))
+	NotInto(dst, a)
+	return dst
+}
+
+// NotInto sets dst[i] = ^a[i] for i < len(a) and returns len(a), panics if `dst` is shorter than
+// `a`. `dst` may be the same slice as `a` for in-place operation.
+func NotInto(dst, a []byte) int {
+	n := len(a)
+	dst = dst[:n]
+	i := 0
+	for ; i+8 <= n; i += 8 {
+		binary.LittleEndian.PutUint64(dst[i:], ^binary.LittleEndian.Uint64(a[i:]))
+	}
+	for ; i < n; i++ {
+		dst[i] = ^a[i]
+	}
+	return n
+}
+
+// ConstantTimeEqual reports whether `a` and `b` are equal in time independent of their content,
+// useful for comparing MACs. The time still depends on the lengths of the slices.
+func ConstantTimeEqual(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// The following functions treat a byte slice as a big-endian bit string, that is, bit 0 is the
+// most significant bit of the first byte.
+
+// PopCount returns the number of one bits in `b`.
+func PopCount(b []byte) int {
+	cnt := 0
+	i := 0
+	for ; i+8 <= len(b); i += 8 {
+		cnt += bits.OnesCount64(binary.LittleEndian.Uint64(b[i:]))
+	}
+	for ; i < len(b); i++ {
+		cnt += bits.OnesCount8(b[i])
+	}
+	return cnt
+}
+
+// LeadingZeros returns the number of leading zero bits in `b`, 8*len(b) if all bits are zero.
+func LeadingZeros(b []byte) int {
+	i := 0
+	for ; i+8 <= len(b); i += 8 {
+		if w := binary.BigEndian.Uint64(b[i:]); w != 0 {
+			return 8*i + bits.LeadingZeros64(w)
+		}
+	}
+	for ; i < len(b); i++ {
+		if b[i] != 0 {
+			return 8*i + bits.LeadingZeros8(b[i])
+		}
+	}
+	return 8 * len(b)
+}
+
+// GetBit reports whether bit `i` of `b` is set, panics if `i` is out of range.
+func GetBit(b []byte, i int) bool {
+	return b[i>>3]&(0x80>>(i&7)) != 0
+}
+
+// SetBit sets bit `i` of `b` to `v`, panics if `i` is out of range.
+func SetBit(b []byte, i int, v bool) {
+	if v {
+		b[i>>3] |= 0x80 >> (i & 7)
+	} else {
+		b[i>>3] &^= 0x80 >> (i & 7)
+	}
+}
+
+// ShiftLeft returns `b` shifted left by `n` bits, towards the first byte. The result has the same
+// length as `b` and the bits shifted out are discarded.
+func ShiftLeft(b []byte, n int) []byte {
+	if n < 0 {
+		panic("bytes: negative shift count")
+	}
+	dst := make([]byte, len(b))
+	byteShift, bitShift := n/8, uint(n%8)
+	for i := range dst {
+		j := i + byteShift
+		if j >= len(b) {
+			break
+		}
+		dst[i] = b[j] << bitShift
+		if bitShift > 0 && j+1 < len(b) {
+			dst[i] |= b[j+1] >> (8 - bitShift)
+		}
+	}
+	return dst
+}
+
+// ShiftRight returns `b` shifted right by `n` bits, towards the last byte. The result has the same
+// length as `b` and the bits shifted out are discarded.
+func ShiftRight(b []byte, n int) []byte {
+	if n < 0 {
+		panic("bytes: negative shift count")
+	}
+	dst := make([]byte, len(b))
+	byteShift, bitShift := n/8, uint(n%8)
+	for i := len(dst) - 1; i >= byteShift; i-- {
+		j := i - byteShift
+		dst[i] = b[j] >> bitShift
+		if bitShift > 0 && j > 0 {
+			dst[i] |= b[j-1] << (8 - bitShift)
+		}
+	}
+	return dst
+}
+
+// XorDistanceCmp compares the XOR distances from `a` and `b` to `target` as used by Kademlia-style
+// routing, returns -1 if `a` is closer, +1 if `b` is closer and 0 if they are equally distant.
+// `a` and `b` must be at least as long as `target`.
+func XorDistanceCmp(target, a, b []byte) int {
+	for i, t := range target {
+		da, db := a[i]^t, b[i]^t
+		switch {
+		case da < db:
+			return -1
+		case da > db:
+			return 1
+		}
+	}
+	return 0
+}
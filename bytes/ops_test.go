@@ -6,6 +6,8 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	. "github.com/daotl/guts/bytes"
 )
 
@@ -30,3 +32,157 @@ func TestConcat(t *testing.T) {
 		}
 	}
 }
+
+func TestConcatInto(t *testing.T) {
+	buf := make([]byte, 1, 8)
+	res := ConcatInto(buf, []byte("OT"), nil, []byte("Z"))
+	if string(res) != "OTZ" {
+		t.Errorf("Expect 'OTZ' but get '%s'", res)
+	}
+	if &res[0] != &buf[0] {
+		t.Error("Expect the buffer to be reused")
+	}
+
+	res = ConcatInto(buf, []byte("0123"), []byte("456789"))
+	if string(res) != "0123456789" {
+		t.Errorf("Expect '0123456789' but get '%s'", res)
+	}
+	if len(ConcatInto(nil)) != 0 {
+		t.Error("Expect empty result")
+	}
+
+	// The inputs sharing memory with `dst` are not overwritten before being copied.
+	buf = make([]byte, 0, 16)
+	buf = append(buf, "body"...)
+	res = ConcatInto(buf, []byte("hdr:"), buf)
+	if string(res) != "hdr:body" {
+		t.Errorf("Expect 'hdr:body' but get '%s'", res)
+	}
+	if string(buf) != "body" {
+		t.Errorf("Expect the input to be intact but get '%s'", buf)
+	}
+	res = ConcatInto(buf[:0], []byte("hdr:"), buf[:4:4])
+	if string(res) != "hdr:body" {
+		t.Errorf("Expect 'hdr:body' but get '%s'", res)
+	}
+}
+
+func TestBitwiseOps(t *testing.T) {
+	assr := assert.New(t)
+
+	a := []byte{0xf0, 0x0f, 0xff, 0x00, 0xaa, 0x55, 0x12, 0x34, 0x56, 0x78}
+	b := []byte{0xff, 0xff, 0x00, 0x00, 0x0f, 0xf0, 0xff, 0x00, 0xff}
+
+	assr.Equal([]byte{0x0f, 0xf0, 0xff, 0x00, 0xa5, 0xa5, 0xed, 0x34, 0xa9}, Xor(a, b))
+	assr.Equal([]byte{0xf0, 0x0f, 0x00, 0x00, 0x0a, 0x50, 0x12, 0x00, 0x56}, And(a, b))
+	assr.Equal([]byte{0xff, 0xff, 0xff, 0x00, 0xaf, 0xf5, 0xff, 0x34, 0xff}, Or(a, b))
+	assr.Equal([]byte{0x0f, 0xf0, 0x00, 0xff, 0x55, 0xaa, 0xed, 0xcb, 0xa9, 0x87}, Not(a))
+
+	// In-place operations.
+	c := Concat(a)
+	assr.Equal(len(b), XorInto(c, c, b))
+	assr.Equal(Xor(a, b), c[:len(b)])
+	assr.Equal(a[len(b):], c[len(b):])
+	c = Concat(a)
+	AndInto(c, c, b)
+	assr.Equal(And(a, b), c[:len(b)])
+	c = Concat(a)
+	OrInto(c, b, c)
+	assr.Equal(Or(a, b), c[:len(b)])
+	c = Concat(a)
+	assr.Equal(len(a), NotInto(c, c))
+	assr.Equal(Not(a), c)
+	assr.Panics(func() { AndInto(make([]byte, 1), a, b) })
+
+	assr.True(ConstantTimeEqual(a, Concat(a)))
+	assr.False(ConstantTimeEqual(a, b))
+	assr.False(ConstantTimeEqual(a[:9], b))
+}
+
+func TestBitOps(t *testing.T) {
+	assr := assert.New(t)
+
+	assr.Equal(0, PopCount(nil))
+	assr.Equal(8*9+1, PopCount(append(Not(make([]byte, 9)), 0x10)))
+	assr.Equal(4, PopCount([]byte{0x0f}))
+
+	assr.Equal(0, LeadingZeros(nil))
+	assr.Equal(0, LeadingZeros([]byte{0x80}))
+	assr.Equal(3, LeadingZeros([]byte{0x10, 0xff}))
+	assr.Equal(8*9+7, LeadingZeros([]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01}))
+	assr.Equal(80, LeadingZeros(make([]byte, 10)))
+
+	b := make([]byte, 2)
+	SetBit(b, 0, true)
+	SetBit(b, 9, true)
+	SetBit(b, 15, true)
+	assr.Equal([]byte{0x80, 0x41}, b)
+	assr.True(GetBit(b, 9))
+	assr.False(GetBit(b, 8))
+	SetBit(b, 9, false)
+	assr.Equal([]byte{0x80, 0x01}, b)
+	assr.Panics(func() { GetBit(b, 16) })
+
+	assr.Equal([]byte{0x23, 0x45, 0x60}, ShiftLeft([]byte{0x12, 0x34, 0x56}, 4))
+	assr.Equal([]byte{0x56, 0x00, 0x00}, ShiftLeft([]byte{0x12, 0x34, 0x56}, 16))
+	assr.Equal([]byte{0x00, 0x00, 0x00}, ShiftLeft([]byte{0x12, 0x34, 0x56}, 100))
+	assr.Equal([]byte{0x24, 0x68, 0xac}, ShiftLeft([]byte{0x12, 0x34, 0x56}, 1))
+	assr.Equal([]byte{0x01, 0x23, 0x45}, ShiftRight([]byte{0x12, 0x34, 0x56}, 4))
+	assr.Equal([]byte{0x00, 0x09, 0x1a}, ShiftRight([]byte{0x12, 0x34, 0x56}, 9))
+	assr.Equal([]byte{0x00, 0x00, 0x00}, ShiftRight([]byte{0x12, 0x34, 0x56}, 24))
+	assr.Equal([]byte{0x12, 0x34}, ShiftRight([]byte{0x12, 0x34}, 0))
+}
+
+func TestXorDistanceCmp(t *testing.T) {
+	assr := assert.New(t)
+
+	target := []byte{0x00, 0xff}
+	assr.Equal(-1, XorDistanceCmp(target, []byte{0x00, 0xf0}, []byte{0x01, 0xff}))
+	assr.Equal(1, XorDistanceCmp(target, []byte{0x80, 0xff}, []byte{0x7f, 0x00}))
+	assr.Equal(0, XorDistanceCmp(target, []byte{0x12, 0x34}, []byte{0x12, 0x34}))
+	assr.Equal(-1, XorDistanceCmp(target, target, []byte{0x00, 0xfe}))
+}
+
+func benchmarkBinaryOp(b *testing.B, op func(dst, a, b []byte) int) {
+	x, y, dst := make([]byte, 4096), make([]byte, 4096), make([]byte, 4096)
+	b.SetBytes(int64(len(dst)))
+	for i := 0; i < b.N; i++ {
+		op(dst, x, y)
+	}
+}
+
+func BenchmarkXorInto(b *testing.B) {
+	benchmarkBinaryOp(b, XorInto)
+}
+
+func BenchmarkAndInto(b *testing.B) {
+	benchmarkBinaryOp(b, AndInto)
+}
+
+func BenchmarkOrInto(b *testing.B) {
+	benchmarkBinaryOp(b, OrInto)
+}
+
+func BenchmarkXorBytewise(b *testing.B) {
+	benchmarkBinaryOp(b, func(dst, x, y []byte) int {
+		for i := range dst {
+			dst[i] = x[i] ^ y[i]
+		}
+		return len(dst)
+	})
+}
+
+func BenchmarkPopCount(b *testing.B) {
+	x := make([]byte, 4096)
+	b.SetBytes(int64(len(x)))
+	for i := 0; i < b.N; i++ {
+		PopCount(x)
+	}
+}
+
+func BenchmarkConstantTimeEqual(b *testing.B) {
+	x, y := make([]byte, 32), make([]byte, 32)
+	for i := 0; i < b.N; i++ {
+		ConstantTimeEqual(x, y)
+	}
+}
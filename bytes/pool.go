@@ -0,0 +1,68 @@
+package bytes
+
+import (
+	"math/bits"
+	"sync"
+)
+
+const (
+	// minPoolClass is the size class of the smallest pooled buffers (64 bytes).
+	minPoolClass = 6
+	// maxPoolClass is the size class of the largest pooled buffers (16 MiB).
+	maxPoolClass = 24
+)
+
+// Pool is a size-classed pool of byte slices built on sync.Pool, it keeps buffers in buckets of
+// power-of-two capacities from 64 bytes to 16 MiB, larger buffers are allocated and dropped
+// without pooling. The zero value is ready to use and it's safe for concurrent use.
+//
+// Building with `pooldebug` flag will track the buffers got from the Pool to detect double puts
+// and leaks, see pool_debug.go.
+type Pool struct {
+	classes [maxPoolClass + 1]sync.Pool
+	tracker poolTracker
+}
+
+// Get returns a buffer of length `n` from the pool, or a newly allocated one if the pool is empty.
+// The content of the buffer is not zeroed. The buffer should be returned with Put when it's no
+// longer used.
+func (p *Pool) Get(n int) []byte {
+	var b []byte
+	if class := poolClass(n); class > maxPoolClass {
+		b = make([]byte, n)
+	} else if v := p.classes[class].Get(); v != nil {
+		b = (*v.(*[]byte))[:n]
+	} else {
+		b = make([]byte, n, 1<<class)
+	}
+	p.tracker.get(b)
+	return b
+}
+
+// Put returns the buffer `b` got from Get to the pool, `b` must not be used afterwards.
+func (p *Pool) Put(b []byte) {
+	p.tracker.put(b)
+
+	size := cap(b)
+	if size < 1<<minPoolClass || size > 1<<maxPoolClass {
+		return
+	}
+	// Round down to the class of which all buffers can hold the class size.
+	class := bits.Len(uint(size)) - 1
+	b = b[:0]
+	p.classes[class].Put(&b)
+}
+
+// CheckLeaks returns an error describing the buffers got but not yet returned if built with
+// `pooldebug` flag, or always nil otherwise.
+func (p *Pool) CheckLeaks() error {
+	return p.tracker.leaks()
+}
+
+// poolClass returns the size class of the buffers able to hold `n` bytes.
+func poolClass(n int) int {
+	if n <= 1<<minPoolClass {
+		return minPoolClass
+	}
+	return bits.Len(uint(n - 1))
+}
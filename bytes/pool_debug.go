@@ -0,0 +1,64 @@
+//go:build pooldebug
+// +build pooldebug
+
+package bytes
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// poolTracker tracks the buffers got from a Pool with the stacks where they were got, to panic on
+// double puts and report leaks.
+//
+// Building with `pooldebug` flag will use this instead of poolTracker in pool_nodebug.go.
+type poolTracker struct {
+	mtx sync.Mutex
+	out map[*byte]string
+}
+
+func (t *poolTracker) get(b []byte) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.out == nil {
+		t.out = make(map[*byte]string)
+	}
+	t.out[poolBufferID(b)] = string(debug.Stack())
+}
+
+func (t *poolTracker) put(b []byte) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	id := poolBufferID(b)
+	if _, ok := t.out[id]; !ok {
+		panic("bytes: Put of a buffer not got from the Pool or already put back")
+	}
+	delete(t.out, id)
+}
+
+func (t *poolTracker) leaks() error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if len(t.out) == 0 {
+		return nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "bytes: %d buffer(s) got from the Pool not put back", len(t.out))
+	for _, stack := range t.out {
+		sb.WriteString("\n\ngot at:\n")
+		sb.WriteString(stack)
+	}
+	return fmt.Errorf("%s", sb.String())
+}
+
+// poolBufferID identifies a buffer by the address of its underlying array.
+func poolBufferID(b []byte) *byte {
+	if cap(b) == 0 {
+		return nil
+	}
+	return unsafe.SliceData(b[:1])
+}
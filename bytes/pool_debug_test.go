@@ -0,0 +1,31 @@
+//go:build pooldebug
+// +build pooldebug
+
+package bytes_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/daotl/guts/bytes"
+)
+
+func TestPoolDebug(t *testing.T) {
+	assr := assert.New(t)
+
+	var p Pool
+	b1, b2 := p.Get(10), p.Get(100)
+	assr.Error(p.CheckLeaks())
+
+	p.Put(b1)
+	assr.Panics(func() { p.Put(b1) }, "double put")
+	assr.Panics(func() { p.Put(make([]byte, 10)) }, "put of a foreign buffer")
+
+	err := p.CheckLeaks()
+	assr.ErrorContains(err, "1 buffer(s)")
+	assr.ErrorContains(err, "TestPoolDebug")
+
+	p.Put(b2)
+	assr.NoError(p.CheckLeaks())
+}
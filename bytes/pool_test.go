@@ -0,0 +1,78 @@
+package bytes_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/daotl/guts/bytes"
+)
+
+func TestPool(t *testing.T) {
+	assr := assert.New(t)
+
+	var p Pool
+	for _, c := range []struct{ n, cap int }{
+		{0, 64}, {1, 64}, {64, 64}, {65, 128}, {1000, 1024}, {1 << 20, 1 << 20}, {1<<24 + 1, 1<<24 + 1},
+	} {
+		b := p.Get(c.n)
+		assr.Len(b, c.n)
+		assr.Equal(c.cap, cap(b), "Get(%d)", c.n)
+		p.Put(b)
+	}
+
+	// A buffer put back can be got again for smaller sizes.
+	b := p.Get(100)
+	p.Put(b[:10])
+	b = p.Get(64)
+	assr.Len(b, 64)
+	assr.GreaterOrEqual(cap(b), 64)
+	p.Put(b)
+	assr.NoError(p.CheckLeaks())
+}
+
+func TestPoolConcurrent(t *testing.T) {
+	var p Pool
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				b := p.Get(j)
+				for k := range b {
+					b[k] = byte(i)
+				}
+				for k := range b {
+					if b[k] != byte(i) {
+						t.Error("buffer shared between goroutines")
+						return
+					}
+				}
+				p.Put(b)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.NoError(t, p.CheckLeaks())
+}
+
+func BenchmarkConcat(b *testing.B) {
+	header, body := make([]byte, 16), make([]byte, 1000)
+
+	b.Run("Concat", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkBytes = Concat(header, body)
+		}
+	})
+	b.Run("ConcatIntoPool", func(b *testing.B) {
+		var p Pool
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf := ConcatInto(p.Get(len(header)+len(body)), header, body)
+			p.Put(buf)
+		}
+	})
+}
@@ -0,0 +1,210 @@
+package bytes
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Size is a number of bytes which is parsed from and formatted to human-readable strings with SI
+// (KB = 1000 bytes) or IEC (KiB = 1024 bytes) units, e.g. "64MiB" or "1.5GB".
+//
+// It's encoded in JSON and text as its exact String, and can be decoded from a JSON number of
+// bytes as well, so it can be used directly in config structs.
+type Size uint64
+
+// Units of Size.
+const (
+	Byte Size = 1
+
+	KB Size = 1000 * Byte
+	MB      = 1000 * KB
+	GB      = 1000 * MB
+	TB      = 1000 * GB
+	PB      = 1000 * TB
+	EB      = 1000 * PB
+
+	KiB Size = 1 << 10
+	MiB Size = 1 << 20
+	GiB Size = 1 << 30
+	TiB Size = 1 << 40
+	PiB Size = 1 << 50
+	EiB Size = 1 << 60
+)
+
+// ErrSizeOverflow is returned when parsing a size that overflows uint64.
+var ErrSizeOverflow = errors.New("size overflows uint64")
+
+var (
+	_ json.Marshaler   = Size(0)
+	_ json.Unmarshaler = (*Size)(nil)
+	_ flag.Value       = (*Size)(nil)
+)
+
+type sizeUnit struct {
+	name string
+	size Size
+}
+
+var (
+	siUnits  = []sizeUnit{{"EB", EB}, {"PB", PB}, {"TB", TB}, {"GB", GB}, {"MB", MB}, {"KB", KB}}
+	iecUnits = []sizeUnit{{"EiB", EiB}, {"PiB", PiB}, {"TiB", TiB}, {"GiB", GiB}, {"MiB", MiB},
+		{"KiB", KiB}}
+	// exactUnits are all the units in descending order.
+	exactUnits = []sizeUnit{{"EiB", EiB}, {"EB", EB}, {"PiB", PiB}, {"PB", PB}, {"TiB", TiB},
+		{"TB", TB}, {"GiB", GiB}, {"GB", GB}, {"MiB", MiB}, {"MB", MB}, {"KiB", KiB}, {"KB", KB}}
+)
+
+// parseSizeUnit returns the Size of a case-insensitive unit name, the "B" suffix is optional, e.g.
+// "k", "KB", "Ki" and "kib" are all accepted.
+func parseSizeUnit(s string) (Size, bool) {
+	s = strings.ToUpper(s)
+	if s == "" || s == "B" {
+		return Byte, true
+	}
+	if !strings.HasSuffix(s, "B") {
+		s += "B"
+	}
+	for _, u := range exactUnits {
+		if strings.ToUpper(u.name) == s {
+			return u.size, true
+		}
+	}
+	return 0, false
+}
+
+// ParseSize parses a size like "1024", "64MiB", "1.5 GB" or "10kb" with a case-insensitive SI or
+// IEC unit. A fractional number of bytes is truncated, e.g. "1.1KiB" is 1126 bytes. Returns
+// ErrSizeOverflow if the size overflows uint64.
+func ParseSize(s string) (Size, error) {
+	str := strings.TrimSpace(s)
+	i := strings.IndexFunc(str, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if i < 0 {
+		i = len(str)
+	}
+	num, unit := str[:i], strings.TrimSpace(str[i:])
+
+	if num == "" || num == "." || strings.Count(num, ".") > 1 {
+		return 0, fmt.Errorf("invalid size: %q", s)
+	}
+	u, ok := parseSizeUnit(unit)
+	if !ok {
+		return 0, fmt.Errorf("invalid size unit: %q", s)
+	}
+
+	// Fast path for integers.
+	if !strings.Contains(num, ".") {
+		n, err := strconv.ParseUint(num, 10, 64)
+		if err != nil || n > math.MaxUint64/uint64(u) {
+			return 0, fmt.Errorf("%w: %q", ErrSizeOverflow, s)
+		}
+		return Size(n) * u, nil
+	}
+
+	r, ok := new(big.Rat).SetString(num)
+	if !ok {
+		return 0, fmt.Errorf("invalid size: %q", s)
+	}
+	r.Mul(r, new(big.Rat).SetUint64(uint64(u)))
+	n := new(big.Int).Quo(r.Num(), r.Denom())
+	if !n.IsUint64() {
+		return 0, fmt.Errorf("%w: %q", ErrSizeOverflow, s)
+	}
+	return Size(n.Uint64()), nil
+}
+
+// Bytes returns the size as uint64.
+func (s Size) Bytes() uint64 {
+	return uint64(s)
+}
+
+// String returns the exact size with the largest SI or IEC unit that divides it, e.g. "64MiB",
+// "1500MB" or "1023B", which ParseSize parses back to the same size.
+func (s Size) String() string {
+	if s != 0 {
+		for _, u := range exactUnits {
+			if s%u.size == 0 {
+				return strconv.FormatUint(uint64(s/u.size), 10) + u.name
+			}
+		}
+	}
+	return strconv.FormatUint(uint64(s), 10) + "B"
+}
+
+// SI returns the size rounded to 2 decimal places with the largest SI unit not exceeding it, e.g.
+// "1.5GB".
+func (s Size) SI() string {
+	return s.human(siUnits)
+}
+
+// IEC returns the size rounded to 2 decimal places with the largest IEC unit not exceeding it,
+// e.g. "1.5GiB".
+func (s Size) IEC() string {
+	return s.human(iecUnits)
+}
+
+// human formats the size with `units` in descending order.
+func (s Size) human(units []sizeUnit) string {
+	for i, u := range units {
+		if s >= u.size {
+			f := strconv.FormatFloat(float64(s)/float64(u.size), 'f', 2, 64)
+			// Promote to the next unit if the size is rounded up to it, e.g. 999999 is "1MB"
+			// instead of "1000KB".
+			if i > 0 {
+				if v, _ := strconv.ParseFloat(f, 64); v >= float64(units[i-1].size/u.size) {
+					u = units[i-1]
+					f = strconv.FormatFloat(float64(s)/float64(u.size), 'f', 2, 64)
+				}
+			}
+			return strings.TrimRight(strings.TrimRight(f, "0"), ".") + u.name
+		}
+	}
+	return strconv.FormatUint(uint64(s), 10) + "B"
+}
+
+// MarshalText implements the encoding.TextMarshaler interface. The encoding is String.
+func (s Size) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface. It accepts the formats of
+// ParseSize.
+func (s *Size) UnmarshalText(text []byte) error {
+	size, err := ParseSize(string(text))
+	if err != nil {
+		return err
+	}
+	*s = size
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface. The encoding is a JSON quoted String.
+func (s Size) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + s.String() + `"`), nil
+}
+
+// UnmarshalJSON implements the json.Umarshaler interface. It accepts a JSON quoted string in the
+// formats of ParseSize or a JSON number of bytes, null leaves the size untouched.
+func (s *Size) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		return s.UnmarshalText(data[1 : len(data)-1])
+	}
+	if len(data) > 0 && data[0] >= '0' && data[0] <= '9' {
+		return s.UnmarshalText(data)
+	}
+	return fmt.Errorf("invalid size: %s", data)
+}
+
+// Set implements the flag.Value interface.
+func (s *Size) Set(str string) error {
+	return s.UnmarshalText([]byte(str))
+}
@@ -0,0 +1,122 @@
+package bytes_test
+
+import (
+	"encoding/json"
+	"flag"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/daotl/guts/bytes"
+)
+
+func TestParseSize(t *testing.T) {
+	assr := assert.New(t)
+
+	cases := []struct {
+		input    string
+		expected Size
+	}{
+		{"0", 0},
+		{"1024", 1024},
+		{"10b", 10},
+		{"64MiB", 64 * MiB},
+		{"64mib", 64 * MiB},
+		{"64Mi", 64 * MiB},
+		{"1.5GB", 1500 * MB},
+		{" 1.5 gb ", 1500 * MB},
+		{"10k", 10 * KB},
+		{"10KB", 10 * KB},
+		{"1.1KiB", 1126},
+		{".5K", 500},
+		{"16EiB", 0},
+		{"15EiB", 15 * EiB},
+		{"18446744073709551615", math.MaxUint64},
+		{"18.446744073709551615EB", math.MaxUint64},
+	}
+	for _, tc := range cases {
+		s, err := ParseSize(tc.input)
+		if tc.expected == 0 && tc.input != "0" {
+			assr.ErrorIs(err, ErrSizeOverflow, tc.input)
+			continue
+		}
+		assr.NoError(err, tc.input)
+		assr.Equal(tc.expected, s, tc.input)
+	}
+
+	for _, input := range []string{"", "MB", "1..5MB", "1.2.3", "1XB", "-1", "1e3", "1 M B"} {
+		_, err := ParseSize(input)
+		assr.Error(err, input)
+	}
+	_, err := ParseSize("18446744073709551616")
+	assr.ErrorIs(err, ErrSizeOverflow)
+	_, err = ParseSize("18.5EB")
+	assr.ErrorIs(err, ErrSizeOverflow)
+}
+
+func TestSizeFormat(t *testing.T) {
+	assr := assert.New(t)
+
+	cases := []struct {
+		size         Size
+		str, si, iec string
+	}{
+		{0, "0B", "0B", "0B"},
+		{1023, "1023B", "1.02KB", "1023B"},
+		{1024, "1KiB", "1.02KB", "1KiB"},
+		{64 * MiB, "64MiB", "67.11MB", "64MiB"},
+		{1500 * MB, "1500MB", "1.5GB", "1.4GiB"},
+		{2 * TB, "2TB", "2TB", "1.82TiB"},
+		// Promoted to the next unit when rounded up to it.
+		{999999, "999999B", "1MB", "976.56KiB"},
+		{1048575, "1048575B", "1.05MB", "1MiB"},
+		{999994999, "999994999B", "999.99MB", "953.67MiB"},
+		{PB - 1, "999999999999999B", "1PB", "909.49TiB"},
+		{math.MaxUint64, "18446744073709551615B", "18.45EB", "16EiB"},
+	}
+	for _, tc := range cases {
+		assr.Equal(tc.str, tc.size.String())
+		assr.Equal(tc.si, tc.size.SI())
+		assr.Equal(tc.iec, tc.size.IEC())
+
+		s, err := ParseSize(tc.size.String())
+		assr.NoError(err)
+		assr.Equal(tc.size, s)
+	}
+}
+
+func TestSizeJSON(t *testing.T) {
+	req := require.New(t)
+
+	type Config struct {
+		Cache Size
+		Limit Size
+	}
+
+	cfg := Config{Cache: 64 * MiB, Limit: 1500 * MB}
+	jsonBytes, err := json.Marshal(cfg)
+	req.NoError(err)
+	req.Equal(`{"Cache":"64MiB","Limit":"1500MB"}`, string(jsonBytes))
+
+	var cfg2 Config
+	req.NoError(json.Unmarshal(jsonBytes, &cfg2))
+	req.Equal(cfg, cfg2)
+
+	req.NoError(json.Unmarshal([]byte(`{"Cache":4096,"Limit":"1.5 GB"}`), &cfg2))
+	req.Equal(Config{Cache: 4 * KiB, Limit: 1500 * MB}, cfg2)
+	req.NoError(json.Unmarshal([]byte(`{"Cache":null}`), &cfg2))
+	req.Equal(4*KiB, cfg2.Cache)
+
+	req.Error(json.Unmarshal([]byte(`{"Cache":"1XB"}`), &cfg2))
+	req.Error(json.Unmarshal([]byte(`{"Cache":-1}`), &cfg2))
+	req.Error(json.Unmarshal([]byte(`{"Cache":true}`), &cfg2))
+
+	// Size can be used as a flag.
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	size := 8 * MiB
+	fs.Var(&size, "cache", "cache size")
+	req.NoError(fs.Parse([]string{"-cache", "1GiB"}))
+	req.Equal(GiB, size)
+}
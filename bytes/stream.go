@@ -0,0 +1,393 @@
+package bytes
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+// Writer writes binary values to a buffer or an io.Writer with a sticky error: after the first
+// error, all following writes are no-ops and Err returns that error, so callers only need to check
+// once at the end.
+//
+// Writer is not safe for concurrent use.
+type Writer struct {
+	codec Codec
+	buf   []byte
+	w     io.Writer
+	n     int64
+	err   error
+}
+
+// NewWriter creates a Writer appending to `dst` with the byte order of `c`.
+func NewWriter(dst []byte, c Codec) *Writer {
+	return &Writer{codec: c, buf: dst}
+}
+
+// NewStreamWriter creates a Writer writing to `w` with the byte order of `c`, each value is
+// written to `w` by a separate Write call, so wrap `w` with bufio.Writer if that's costly.
+func NewStreamWriter(w io.Writer, c Codec) *Writer {
+	return &Writer{codec: c, w: w}
+}
+
+// begin returns the buffer to append the next value to.
+func (w *Writer) begin() []byte {
+	if w.w != nil {
+		return w.buf[:0]
+	}
+	return w.buf
+}
+
+// end finishes writing the value appended to the buffer returned by begin.
+func (w *Writer) end(b []byte) {
+	if w.w == nil {
+		w.n += int64(len(b) - len(w.buf))
+		w.buf = b
+		return
+	}
+
+	// Keep the buffer as scratch space for the next value.
+	w.buf = b[:0]
+	w.write(b)
+}
+
+// write writes `b` to the underlying io.Writer.
+func (w *Writer) write(b []byte) {
+	n, err := w.w.Write(b)
+	w.n += int64(n)
+	if err == nil && n < len(b) {
+		err = io.ErrShortWrite
+	}
+	w.err = err
+}
+
+// U08 writes an uint8.
+func (w *Writer) U08(val uint8) {
+	if w.err == nil {
+		w.end(append(w.begin(), val))
+	}
+}
+
+// U16 writes an uint16.
+func (w *Writer) U16(val uint16) {
+	if w.err == nil {
+		w.end(w.codec.AppendU16(w.begin(), val))
+	}
+}
+
+// U32 writes an uint32.
+func (w *Writer) U32(val uint32) {
+	if w.err == nil {
+		w.end(w.codec.AppendU32(w.begin(), val))
+	}
+}
+
+// U64 writes an uint64.
+func (w *Writer) U64(val uint64) {
+	if w.err == nil {
+		w.end(w.codec.AppendU64(w.begin(), val))
+	}
+}
+
+// I08 writes an int8.
+func (w *Writer) I08(val int8) {
+	w.U08(uint8(val))
+}
+
+// I16 writes an int16.
+func (w *Writer) I16(val int16) {
+	w.U16(uint16(val))
+}
+
+// I32 writes an int32.
+func (w *Writer) I32(val int32) {
+	w.U32(uint32(val))
+}
+
+// I64 writes an int64.
+func (w *Writer) I64(val int64) {
+	w.U64(uint64(val))
+}
+
+// Uvarint writes an unsigned LEB128 varint.
+func (w *Writer) Uvarint(val uint64) {
+	if w.err == nil {
+		w.end(AppendUvarint(w.begin(), val))
+	}
+}
+
+// Varint writes a zigzag varint.
+func (w *Writer) Varint(val int64) {
+	if w.err == nil {
+		w.end(AppendVarint(w.begin(), val))
+	}
+}
+
+// Raw writes `b` as is.
+func (w *Writer) Raw(b []byte) {
+	switch {
+	case w.err != nil:
+	case w.w != nil:
+		w.write(b)
+	default:
+		w.end(append(w.buf, b...))
+	}
+}
+
+// LenBytes writes `b` prefixed with its length as an unsigned varint.
+func (w *Writer) LenBytes(b []byte) {
+	w.Uvarint(uint64(len(b)))
+	w.Raw(b)
+}
+
+// LenString writes `s` prefixed with its length as an unsigned varint.
+func (w *Writer) LenString(s string) {
+	w.LenBytes(UnsafeBytes(s))
+}
+
+// Bytes returns the buffer with the written bytes appended, it's always nil for a stream Writer.
+func (w *Writer) Bytes() []byte {
+	if w.w != nil {
+		return nil
+	}
+	return w.buf
+}
+
+// Len returns the number of bytes written so far.
+func (w *Writer) Len() int64 {
+	return w.n
+}
+
+// Err returns the first error occurred.
+func (w *Writer) Err() error {
+	return w.err
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// Reader reads binary values from a buffer or an io.Reader with a sticky error: after the first
+// error, all following reads return zero values and Err returns that error, so callers only need
+// to check once at the end.
+//
+// Reading past the end of a buffer results in ErrShortBuffer, while reading past the end of an
+// io.Reader results in io.EOF or io.ErrUnexpectedEOF as io.ReadFull does.
+//
+// Reader is not safe for concurrent use.
+type Reader struct {
+	codec   Codec
+	buf     []byte
+	r       io.Reader
+	scratch [8]byte
+	n       int64
+	err     error
+}
+
+// NewReader creates a Reader reading from `b` with the byte order of `c`.
+func NewReader(b []byte, c Codec) *Reader {
+	return &Reader{codec: c, buf: b}
+}
+
+// NewStreamReader creates a Reader reading from `r` with the byte order of `c`, wrap `r` with
+// bufio.Reader if small reads are costly.
+func NewStreamReader(r io.Reader, c Codec) *Reader {
+	return &Reader{codec: c, r: r}
+}
+
+// next returns the next `n` bytes, or nil if there is an error. For a stream Reader, the returned
+// slice is only valid until the next call if `n` fits in the scratch buffer.
+func (r *Reader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+
+	if r.r == nil {
+		if len(r.buf) < n {
+			r.err = shortBuffer(n, len(r.buf))
+			return nil
+		}
+		b := r.buf[:n:n]
+		r.buf = r.buf[n:]
+		r.n += int64(n)
+		return b
+	}
+
+	var b []byte
+	if n <= len(r.scratch) {
+		b = r.scratch[:n]
+	} else {
+		b = make([]byte, n)
+	}
+	m, err := io.ReadFull(r.r, b)
+	r.n += int64(m)
+	if err != nil {
+		r.err = err
+		return nil
+	}
+	return b
+}
+
+// U08 reads an uint8.
+func (r *Reader) U08() uint8 {
+	if b := r.next(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+// U16 reads an uint16.
+func (r *Reader) U16() uint16 {
+	if b := r.next(2); b != nil {
+		return r.codec.ToU16(b)
+	}
+	return 0
+}
+
+// U32 reads an uint32.
+func (r *Reader) U32() uint32 {
+	if b := r.next(4); b != nil {
+		return r.codec.ToU32(b)
+	}
+	return 0
+}
+
+// U64 reads an uint64.
+func (r *Reader) U64() uint64 {
+	if b := r.next(8); b != nil {
+		return r.codec.ToU64(b)
+	}
+	return 0
+}
+
+// I08 reads an int8.
+func (r *Reader) I08() int8 {
+	return int8(r.U08())
+}
+
+// I16 reads an int16.
+func (r *Reader) I16() int16 {
+	return int16(r.U16())
+}
+
+// I32 reads an int32.
+func (r *Reader) I32() int32 {
+	return int32(r.U32())
+}
+
+// I64 reads an int64.
+func (r *Reader) I64() int64 {
+	return int64(r.U64())
+}
+
+// Uvarint reads an unsigned LEB128 varint, sets ErrVarintOverflow if the value overflows uint64.
+func (r *Reader) Uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+
+	if r.r == nil {
+		val, rest, err := ReadUvarint(r.buf)
+		if err != nil {
+			r.err = err
+			return 0
+		}
+		r.n += int64(len(r.buf) - len(rest))
+		r.buf = rest
+		return val
+	}
+
+	var val uint64
+	for i := 0; i < MaxUvarintLen; i++ {
+		b := r.next(1)
+		if b == nil {
+			if i > 0 && r.err == io.EOF {
+				r.err = io.ErrUnexpectedEOF
+			}
+			return 0
+		}
+		if b[0] < 0x80 {
+			if i == MaxUvarintLen-1 && b[0] > 1 {
+				break
+			}
+			return val | uint64(b[0])<<(7*i)
+		}
+		val |= uint64(b[0]&0x7f) << (7 * i)
+	}
+	r.err = ErrVarintOverflow
+	return 0
+}
+
+// Varint reads a zigzag varint, sets ErrVarintOverflow if the value overflows int64.
+func (r *Reader) Varint() int64 {
+	return ZigZagDecode(r.Uvarint())
+}
+
+// Raw reads `n` bytes. For a buffer Reader, the returned slice shares the underlying array of the
+// buffer.
+func (r *Reader) Raw(n int) []byte {
+	if n < 0 {
+		if r.err == nil {
+			r.err = fmt.Errorf("negative length %d", n)
+		}
+		return nil
+	}
+	return r.raw(uint64(n))
+}
+
+// LenBytes reads bytes prefixed with their length as an unsigned varint. For a buffer Reader, the
+// returned slice shares the underlying array of the buffer.
+func (r *Reader) LenBytes() []byte {
+	n := r.Uvarint()
+	return r.raw(n)
+}
+
+func (r *Reader) raw(n uint64) []byte {
+	if r.err != nil {
+		return nil
+	}
+
+	if r.r == nil {
+		if n > uint64(len(r.buf)) {
+			r.err = shortBuffer(int(min(n, math.MaxInt)), len(r.buf))
+			return nil
+		}
+		return r.next(int(n))
+	}
+
+	// Read progressively so that a bogus length can't cause a huge allocation.
+	b, err := io.ReadAll(io.LimitReader(r.r, int64(min(n, math.MaxInt64))))
+	r.n += int64(len(b))
+	if err == nil && uint64(len(b)) < n {
+		err = io.ErrUnexpectedEOF
+		if len(b) == 0 {
+			err = io.EOF
+		}
+	}
+	if err != nil {
+		r.err = err
+		return nil
+	}
+	return b
+}
+
+// LenString reads a string prefixed with its length as an unsigned varint.
+func (r *Reader) LenString() string {
+	return string(r.LenBytes())
+}
+
+// Remaining returns the unread bytes of a buffer Reader, it's always nil for a stream Reader.
+func (r *Reader) Remaining() []byte {
+	if r.r != nil {
+		return nil
+	}
+	return r.buf
+}
+
+// Len returns the number of bytes read so far.
+func (r *Reader) Len() int64 {
+	return r.n
+}
+
+// Err returns the first error occurred.
+func (r *Reader) Err() error {
+	return r.err
+}
@@ -0,0 +1,169 @@
+package bytes_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/daotl/guts/bytes"
+)
+
+func writeTestFrame(w *Writer) {
+	w.U08(0x01)
+	w.U16(0x0203)
+	w.U32(0x04050607)
+	w.U64(0x08090a0b0c0d0e0f)
+	w.I08(-1)
+	w.I16(-2)
+	w.I32(-3)
+	w.I64(-4)
+	w.Uvarint(300)
+	w.Varint(-300)
+	w.LenBytes([]byte("abc"))
+	w.LenString("hello")
+	w.Raw([]byte{0xff, 0xfe})
+}
+
+func readTestFrame(t *testing.T, r *Reader) {
+	assr := assert.New(t)
+	assr.Equal(uint8(0x01), r.U08())
+	assr.Equal(uint16(0x0203), r.U16())
+	assr.Equal(uint32(0x04050607), r.U32())
+	assr.Equal(uint64(0x08090a0b0c0d0e0f), r.U64())
+	assr.Equal(int8(-1), r.I08())
+	assr.Equal(int16(-2), r.I16())
+	assr.Equal(int32(-3), r.I32())
+	assr.Equal(int64(-4), r.I64())
+	assr.Equal(uint64(300), r.Uvarint())
+	assr.Equal(int64(-300), r.Varint())
+	assr.Equal([]byte("abc"), r.LenBytes())
+	assr.Equal("hello", r.LenString())
+	assr.Equal([]byte{0xff, 0xfe}, r.Raw(2))
+	assr.NoError(r.Err())
+}
+
+func TestWriterReader(t *testing.T) {
+	req := require.New(t)
+
+	for _, c := range []Codec{LittleEndian, BigEndian} {
+		w := NewWriter([]byte{0xaa}, c)
+		writeTestFrame(w)
+		req.NoError(w.Err())
+		req.Equal(int64(len(w.Bytes())-1), w.Len())
+		req.Equal(byte(0xaa), w.Bytes()[0])
+
+		var buf bytes.Buffer
+		sw := NewStreamWriter(&buf, c)
+		writeTestFrame(sw)
+		req.NoError(sw.Err())
+		req.Nil(sw.Bytes())
+		req.Equal(w.Bytes()[1:], buf.Bytes())
+		req.Equal(w.Len(), sw.Len())
+
+		r := NewReader(w.Bytes()[1:], c)
+		readTestFrame(t, r)
+		req.Empty(r.Remaining())
+		req.Equal(w.Len(), r.Len())
+
+		sr := NewStreamReader(&buf, c)
+		readTestFrame(t, sr)
+		req.Equal(w.Len(), sr.Len())
+		sr.U08()
+		req.ErrorIs(sr.Err(), io.EOF)
+	}
+
+	w := NewWriter(nil, BigEndian)
+	w.U32(0x01020304)
+	req.Equal([]byte{1, 2, 3, 4}, w.Bytes())
+}
+
+func TestReaderStickyError(t *testing.T) {
+	assr := assert.New(t)
+
+	w := NewWriter(nil, LittleEndian)
+	w.U16(1)
+	w.LenString("abc")
+	b := w.Bytes()
+
+	// Truncated in the middle of the string.
+	r := NewReader(b[:len(b)-1], LittleEndian)
+	assr.Equal(uint16(1), r.U16())
+	assr.Equal("", r.LenString())
+	assr.ErrorIs(r.Err(), ErrShortBuffer)
+	assr.Equal(uint64(0), r.U64())
+	assr.ErrorIs(r.Err(), ErrShortBuffer)
+
+	sr := NewStreamReader(bytes.NewReader(b[:len(b)-1]), LittleEndian)
+	assr.Equal(uint16(1), sr.U16())
+	assr.Equal("", sr.LenString())
+	assr.ErrorIs(sr.Err(), io.ErrUnexpectedEOF)
+
+	// A bogus length doesn't allocate the whole length.
+	sr = NewStreamReader(bytes.NewReader(AppendUvarint(nil, 1<<62)), LittleEndian)
+	assr.Nil(sr.LenBytes())
+	assr.ErrorIs(sr.Err(), io.EOF)
+
+	r = NewReader(AppendUvarint(nil, 1<<62), LittleEndian)
+	assr.Nil(r.LenBytes())
+	assr.ErrorIs(r.Err(), ErrShortBuffer)
+
+	// Truncated and overflowing varints.
+	sr = NewStreamReader(bytes.NewReader([]byte{0x80}), LittleEndian)
+	sr.Uvarint()
+	assr.ErrorIs(sr.Err(), io.ErrUnexpectedEOF)
+	overflow := bytes.Repeat([]byte{0xff}, MaxUvarintLen+1)
+	sr = NewStreamReader(bytes.NewReader(overflow), LittleEndian)
+	sr.Uvarint()
+	assr.ErrorIs(sr.Err(), ErrVarintOverflow)
+	r = NewReader(overflow, LittleEndian)
+	r.Varint()
+	assr.ErrorIs(r.Err(), ErrVarintOverflow)
+
+	r = NewReader(b, LittleEndian)
+	assr.Nil(r.Raw(-1))
+	assr.Error(r.Err())
+}
+
+type failingWriter struct {
+	n int
+}
+
+var errTestWrite = errors.New("write failed")
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.n < len(p) {
+		n := w.n
+		w.n = 0
+		return n, errTestWrite
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestWriterStickyError(t *testing.T) {
+	assr := assert.New(t)
+
+	w := NewStreamWriter(&failingWriter{n: 5}, LittleEndian)
+	w.U32(1)
+	assr.NoError(w.Err())
+	w.U32(2)
+	assr.ErrorIs(w.Err(), errTestWrite)
+	w.LenString("abc")
+	assr.ErrorIs(w.Err(), errTestWrite)
+	assr.Equal(int64(5), w.Len())
+}
+
+func BenchmarkWriter(b *testing.B) {
+	buf := make([]byte, 0, 64)
+	for i := 0; i < b.N; i++ {
+		w := NewWriter(buf[:0], LittleEndian)
+		w.U32(uint32(i))
+		w.Uvarint(uint64(i))
+		w.LenString("hello")
+		sinkBytes = w.Bytes()
+	}
+}
@@ -0,0 +1,256 @@
+package bytes
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Type codes of encoded tuple elements, the order of the codes defines the order between elements
+// of different types.
+const (
+	tupleNil    byte = 0x00
+	tupleBytes  byte = 0x01
+	tupleString byte = 0x02
+	tupleNested byte = 0x05
+	tupleInt    byte = 0x14
+	tupleUint   byte = 0x15
+	tupleFloat  byte = 0x21
+	tupleFalse  byte = 0x26
+	tupleTrue   byte = 0x27
+
+	// tupleEscape follows a 0x00 byte inside a string, a []byte or a nested tuple to tell it from
+	// a terminator.
+	tupleEscape byte = 0xff
+)
+
+// ErrInvalidTuple is returned when decoding malformed tuple bytes.
+var ErrInvalidTuple = errors.New("invalid tuple encoding")
+
+// Tuple is an ordered list of elements which can be encoded into an order-preserving key, that is,
+// `bytes.Compare` on two encoded tuples matches the natural order of the tuples, useful for keys of
+// ordered key-value stores like LevelDB or Badger.
+//
+// Supported element types are nil, bool, all integer types, float32, float64, string, []byte,
+// HexBytes and nested Tuple or []any. Elements are first ordered by type in the following order:
+// nil, []byte, string, nested tuples, signed integers, unsigned integers, floats and booleans,
+// then by value within the same type:
+//   - Integers of different widths are ordered by value, but signed and unsigned integers are
+//     ordered separately, so use consistent types in a key schema.
+//   - Strings and []byte are ordered lexicographically and a prefix sorts first.
+//   - Floats are ordered numerically except that -0 sorts before +0, and NaNs sort before -Inf
+//     (negative) or after +Inf (positive).
+//   - Shorter tuples sort before longer tuples sharing the same prefix.
+//
+// Decoding returns elements as nil, bool, int64, uint64, float64, string, []byte or Tuple.
+type Tuple []any
+
+// Encode encodes the tuple into an order-preserving key.
+func (t Tuple) Encode() ([]byte, error) {
+	return t.Append(nil)
+}
+
+// Append appends the encoded tuple to `dst` and returns the extended buffer.
+func (t Tuple) Append(dst []byte) ([]byte, error) {
+	return appendTupleElems(dst, t, false)
+}
+
+// EncodeTuple encodes the given elements into an order-preserving key as a Tuple.
+func EncodeTuple(elems ...any) ([]byte, error) {
+	return Tuple(elems).Encode()
+}
+
+// DecodeTuple decodes a key encoded by Tuple.Encode.
+func DecodeTuple(b []byte) (Tuple, error) {
+	t := Tuple{}
+	for len(b) > 0 {
+		elem, rest, err := decodeTupleElem(b, false)
+		if err != nil {
+			return nil, err
+		}
+		t = append(t, elem)
+		b = rest
+	}
+	return t, nil
+}
+
+func appendTupleElems(dst []byte, elems []any, nested bool) ([]byte, error) {
+	var err error
+	for i, elem := range elems {
+		if dst, err = appendTupleElem(dst, elem, nested); err != nil {
+			return nil, fmt.Errorf("tuple element %d: %w", i, err)
+		}
+	}
+	return dst, nil
+}
+
+func appendTupleElem(dst []byte, elem any, nested bool) ([]byte, error) {
+	switch v := elem.(type) {
+	case nil:
+		if nested {
+			return append(dst, tupleNil, tupleEscape), nil
+		}
+		return append(dst, tupleNil), nil
+	case []byte:
+		return appendTupleTerminated(append(dst, tupleBytes), v), nil
+	case HexBytes:
+		return appendTupleTerminated(append(dst, tupleBytes), v), nil
+	case string:
+		return appendTupleTerminated(append(dst, tupleString), []byte(v)), nil
+	case Tuple:
+		return appendTupleNested(dst, v)
+	case []any:
+		return appendTupleNested(dst, v)
+	case int:
+		return appendTupleInt(dst, int64(v)), nil
+	case int8:
+		return appendTupleInt(dst, int64(v)), nil
+	case int16:
+		return appendTupleInt(dst, int64(v)), nil
+	case int32:
+		return appendTupleInt(dst, int64(v)), nil
+	case int64:
+		return appendTupleInt(dst, v), nil
+	case uint:
+		return BigEndian.AppendU64(append(dst, tupleUint), uint64(v)), nil
+	case uint8:
+		return BigEndian.AppendU64(append(dst, tupleUint), uint64(v)), nil
+	case uint16:
+		return BigEndian.AppendU64(append(dst, tupleUint), uint64(v)), nil
+	case uint32:
+		return BigEndian.AppendU64(append(dst, tupleUint), uint64(v)), nil
+	case uint64:
+		return BigEndian.AppendU64(append(dst, tupleUint), v), nil
+	case float32:
+		return appendTupleFloat(dst, float64(v)), nil
+	case float64:
+		return appendTupleFloat(dst, v), nil
+	case bool:
+		if v {
+			return append(dst, tupleTrue), nil
+		}
+		return append(dst, tupleFalse), nil
+	default:
+		return nil, fmt.Errorf("unsupported type %T", elem)
+	}
+}
+
+// appendTupleTerminated appends `b` with 0x00 escaped as 0x00 0xff and terminated by 0x00.
+func appendTupleTerminated(dst []byte, b []byte) []byte {
+	for _, c := range b {
+		dst = append(dst, c)
+		if c == 0x00 {
+			dst = append(dst, tupleEscape)
+		}
+	}
+	return append(dst, 0x00)
+}
+
+func appendTupleNested(dst []byte, elems []any) ([]byte, error) {
+	dst, err := appendTupleElems(append(dst, tupleNested), elems, true)
+	if err != nil {
+		return nil, err
+	}
+	return append(dst, 0x00), nil
+}
+
+// appendTupleInt appends `v` in big endian with the sign bit flipped so that negative numbers sort
+// before positive ones.
+func appendTupleInt(dst []byte, v int64) []byte {
+	return BigEndian.AppendU64(append(dst, tupleInt), uint64(v)^(1<<63))
+}
+
+// appendTupleFloat appends the IEEE 754 bits of `v` in big endian, flipping all bits of negative
+// numbers and only the sign bit of positive ones so that the bytes sort numerically.
+func appendTupleFloat(dst []byte, v float64) []byte {
+	bits := math.Float64bits(v)
+	if bits&(1<<63) != 0 {
+		bits = ^bits
+	} else {
+		bits ^= 1 << 63
+	}
+	return BigEndian.AppendU64(append(dst, tupleFloat), bits)
+}
+
+func decodeTupleElem(b []byte, nested bool) (any, []byte, error) {
+	code, b := b[0], b[1:]
+	switch code {
+	case tupleNil:
+		if nested {
+			// The caller has checked that it's followed by tupleEscape.
+			return nil, b[1:], nil
+		}
+		return nil, b, nil
+	case tupleBytes:
+		return decodeTupleTerminated(b)
+	case tupleString:
+		v, rest, err := decodeTupleTerminated(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		return string(v), rest, nil
+	case tupleNested:
+		t := Tuple{}
+		for {
+			if len(b) == 0 {
+				return nil, nil, fmt.Errorf("%w: unterminated nested tuple", ErrInvalidTuple)
+			}
+			if b[0] == 0x00 && (len(b) == 1 || b[1] != tupleEscape) {
+				return t, b[1:], nil
+			}
+			elem, rest, err := decodeTupleElem(b, true)
+			if err != nil {
+				return nil, nil, err
+			}
+			t = append(t, elem)
+			b = rest
+		}
+	case tupleInt:
+		v, rest, err := BigEndian.ReadU64(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		return int64(v ^ (1 << 63)), rest, nil
+	case tupleUint:
+		v, rest, err := BigEndian.ReadU64(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		return v, rest, nil
+	case tupleFloat:
+		bits, rest, err := BigEndian.ReadU64(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		if bits&(1<<63) != 0 {
+			bits ^= 1 << 63
+		} else {
+			bits = ^bits
+		}
+		return math.Float64frombits(bits), rest, nil
+	case tupleFalse:
+		return false, b, nil
+	case tupleTrue:
+		return true, b, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: unknown type code 0x%02x", ErrInvalidTuple, code)
+	}
+}
+
+// decodeTupleTerminated decodes bytes encoded by appendTupleTerminated.
+func decodeTupleTerminated(b []byte) ([]byte, []byte, error) {
+	v := []byte{}
+	for i := 0; i < len(b); i++ {
+		if b[i] != 0x00 {
+			v = append(v, b[i])
+			continue
+		}
+		if i+1 < len(b) && b[i+1] == tupleEscape {
+			v = append(v, 0x00)
+			i++
+			continue
+		}
+		return v, b[i+1:], nil
+	}
+	return nil, nil, fmt.Errorf("%w: unterminated string or bytes", ErrInvalidTuple)
+}
@@ -0,0 +1,231 @@
+package bytes_test
+
+import (
+	"bytes"
+	"cmp"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/daotl/guts/bytes"
+)
+
+// tupleTypeRank returns the rank of the type of a decoded tuple element in the encoded order.
+func tupleTypeRank(v any) int {
+	switch v.(type) {
+	case nil:
+		return 0
+	case []byte:
+		return 1
+	case string:
+		return 2
+	case Tuple:
+		return 3
+	case int64:
+		return 4
+	case uint64:
+		return 5
+	case float64:
+		return 6
+	case bool:
+		return 7
+	}
+	panic("unexpected type")
+}
+
+// compareTuples compares two tuples in their natural order.
+func compareTuples(a, b Tuple) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := compareTupleElems(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+func compareTupleElems(a, b any) int {
+	if c := cmp.Compare(tupleTypeRank(a), tupleTypeRank(b)); c != 0 {
+		return c
+	}
+	switch a := a.(type) {
+	case []byte:
+		return bytes.Compare(a, b.([]byte))
+	case string:
+		return cmp.Compare(a, b.(string))
+	case Tuple:
+		return compareTuples(a, b.(Tuple))
+	case int64:
+		return cmp.Compare(a, b.(int64))
+	case uint64:
+		return cmp.Compare(a, b.(uint64))
+	case float64:
+		return cmp.Compare(a, b.(float64))
+	case bool:
+		switch {
+		case a == b.(bool):
+			return 0
+		case a:
+			return 1
+		default:
+			return -1
+		}
+	}
+	return 0
+}
+
+// randTuple generates a random tuple, strings and []byte are drawn from a small alphabet including
+// 0x00 and 0xff to exercise the escaping.
+func randTuple(r *rand.Rand, depth int) Tuple {
+	alphabet := []byte{0x00, 0x01, 'a', 'b', 0xfe, 0xff}
+	randBytes := func() []byte {
+		b := make([]byte, r.Intn(4))
+		for i := range b {
+			b[i] = alphabet[r.Intn(len(alphabet))]
+		}
+		return b
+	}
+
+	t := make(Tuple, r.Intn(4))
+	for i := range t {
+		kind := r.Intn(8)
+		if kind == 3 && depth == 0 {
+			kind = 0
+		}
+		switch kind {
+		case 0:
+			t[i] = nil
+		case 1:
+			t[i] = randBytes()
+		case 2:
+			t[i] = string(randBytes())
+		case 3:
+			t[i] = randTuple(r, depth-1)
+		case 4:
+			t[i] = r.Int63n(7) - 3
+		case 5:
+			t[i] = uint64(r.Int63n(7))
+		case 6:
+			t[i] = float64(r.Int63n(7)-3) / 2
+		case 7:
+			t[i] = r.Intn(2) == 1
+		}
+	}
+	return t
+}
+
+func TestTupleRoundTrip(t *testing.T) {
+	req := require.New(t)
+
+	tuples := []Tuple{
+		{},
+		{nil},
+		{nil, nil},
+		{[]byte{}, ""},
+		{[]byte{0x00, 0xff, 0x00}, "a\x00b"},
+		{int64(math.MinInt64), int64(-1), int64(0), int64(math.MaxInt64)},
+		{uint64(0), uint64(math.MaxUint64)},
+		{math.Inf(-1), -1.5, 0.0, 2.25, math.Inf(1)},
+		{true, false},
+		{Tuple{}, Tuple{nil}, Tuple{Tuple{nil, "x"}, int64(1)}, nil},
+	}
+	for _, tup := range tuples {
+		enc, err := tup.Encode()
+		req.NoError(err)
+		dec, err := DecodeTuple(enc)
+		req.NoError(err)
+		req.Equal(tup, dec)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		tup := randTuple(r, 2)
+		enc, err := tup.Encode()
+		req.NoError(err)
+		dec, err := DecodeTuple(enc)
+		req.NoError(err)
+		req.Equal(tup, dec)
+	}
+}
+
+func TestTupleNormalizesTypes(t *testing.T) {
+	req := require.New(t)
+
+	enc, err := EncodeTuple(int8(-1), uint16(2), float32(0.5), HexBytes{0x01}, []any{7})
+	req.NoError(err)
+	dec, err := DecodeTuple(enc)
+	req.NoError(err)
+	req.Equal(Tuple{int64(-1), uint64(2), 0.5, []byte{0x01}, Tuple{int64(7)}}, dec)
+
+	_, err = EncodeTuple(struct{}{})
+	req.Error(err)
+}
+
+func TestTupleDecodeErrors(t *testing.T) {
+	assr := assert.New(t)
+
+	for _, enc := range [][]byte{
+		{0x01, 'a'},
+		{0x02},
+		{0x05, 0x14},
+		{0x05},
+		{0x14, 0x00},
+		{0x99},
+	} {
+		_, err := DecodeTuple(enc)
+		assr.Error(err, "%x", enc)
+	}
+
+	_, err := DecodeTuple([]byte{0x99})
+	assr.ErrorIs(err, ErrInvalidTuple)
+	_, err = DecodeTuple([]byte{0x15, 0x00})
+	assr.ErrorIs(err, ErrShortBuffer)
+}
+
+func TestTupleOrderProperties(t *testing.T) {
+	// orderPreserved checks that the order of the encoded tuples matches the order of the tuples.
+	orderPreserved := func(a, b Tuple) bool {
+		encA, err := a.Encode()
+		if err != nil {
+			return false
+		}
+		encB, err := b.Encode()
+		if err != nil {
+			return false
+		}
+		return bytes.Compare(encA, encB) == compareTuples(a, b)
+	}
+
+	cfg := &quick.Config{MaxCount: 2000}
+	properties := []any{
+		func(a, b int64) bool { return orderPreserved(Tuple{a}, Tuple{b}) },
+		func(a, b uint64) bool { return orderPreserved(Tuple{a}, Tuple{b}) },
+		func(a, b float64) bool { return orderPreserved(Tuple{a}, Tuple{b}) },
+		func(a, b string) bool { return orderPreserved(Tuple{a}, Tuple{b}) },
+		func(a, b []byte) bool {
+			// quick generates nil or non-nil slices, normalize them to match decoded values.
+			return orderPreserved(Tuple{append([]byte{}, a...)}, Tuple{append([]byte{}, b...)})
+		},
+		func(a, b bool) bool { return orderPreserved(Tuple{a}, Tuple{b}) },
+		func(a1, b1 string, a2, b2 int64) bool {
+			return orderPreserved(Tuple{a1, a2}, Tuple{b1, b2})
+		},
+	}
+	for _, p := range properties {
+		if err := quick.Check(p, cfg); err != nil {
+			t.Errorf("%s: %v", reflect.TypeOf(p), err)
+		}
+	}
+
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 5000; i++ {
+		a, b := randTuple(r, 2), randTuple(r, 2)
+		if !orderPreserved(a, b) {
+			t.Fatalf("order not preserved for %v and %v", a, b)
+		}
+	}
+}
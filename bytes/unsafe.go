@@ -0,0 +1,27 @@
+package bytes
+
+import (
+	"unsafe"
+)
+
+// UnsafeString converts `b` to a string without copying.
+//
+// The returned string shares the underlying memory with `b`, so `b` must not be modified
+// afterwards as long as the string is in use, otherwise the immutability of strings is broken.
+func UnsafeString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	return unsafe.String(unsafe.SliceData(b), len(b))
+}
+
+// UnsafeBytes converts `s` to []byte without copying.
+//
+// The returned slice shares the underlying memory with `s` and must never be modified, modifying it
+// is undefined behavior and may crash the program since string data can reside in read-only memory.
+func UnsafeBytes(s string) []byte {
+	if len(s) == 0 {
+		return nil
+	}
+	return unsafe.Slice(unsafe.StringData(s), len(s))
+}
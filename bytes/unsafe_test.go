@@ -0,0 +1,71 @@
+package bytes_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/daotl/guts/bytes"
+)
+
+func TestUnsafeConversions(t *testing.T) {
+	assr := assert.New(t)
+
+	b := []byte("hello")
+	s := UnsafeString(b)
+	assr.Equal("hello", s)
+	// The string shares the memory with the slice.
+	b[0] = 'j'
+	assr.Equal("jello", s)
+
+	assr.Equal([]byte("world"), UnsafeBytes("world"))
+	assr.Equal("", UnsafeString(nil))
+	assr.Nil(UnsafeBytes(""))
+	assr.Equal(cap(UnsafeBytes("world")), 5)
+}
+
+var (
+	benchBytes  = []byte("The quick brown fox jumps over the lazy dog, 0123456789")
+	benchString = string(benchBytes)
+
+	sinkString string
+	sinkBytes  []byte
+)
+
+func BenchmarkBytesToString(b *testing.B) {
+	b.Run("Copy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkString = string(benchBytes)
+		}
+	})
+	b.Run("Unsafe", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkString = UnsafeString(benchBytes)
+		}
+	})
+}
+
+func BenchmarkStringToBytes(b *testing.B) {
+	b.Run("Copy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkBytes = []byte(benchString)
+		}
+	})
+	b.Run("Unsafe", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkBytes = UnsafeBytes(benchString)
+		}
+	})
+}
+
+func BenchmarkHexBytesString(b *testing.B) {
+	bz := HexBytes(benchBytes)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sinkString = bz.String()
+	}
+}
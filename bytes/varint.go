@@ -0,0 +1,133 @@
+package bytes
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+const (
+	// MaxUvarintLen is the maximum length of an unsigned or zigzag varint-encoded 64-bit integer.
+	MaxUvarintLen = binary.MaxVarintLen64
+
+	// MaxPrefixVarintLen is the maximum length of a prefix varint-encoded 64-bit integer.
+	MaxPrefixVarintLen = 9
+)
+
+// ErrVarintOverflow is returned when decoding a varint that overflows a 64-bit integer.
+var ErrVarintOverflow = errors.New("varint overflows a 64-bit integer")
+
+/////////////////////////////////////////////////////////////////////////////
+
+// AppendUvarint appends the unsigned LEB128 varint encoding of `val` to `dst` and returns the
+// extended buffer.
+func AppendUvarint(dst []byte, val uint64) []byte {
+	return binary.AppendUvarint(dst, val)
+}
+
+// ReadUvarint reads an unsigned LEB128 varint from the beginning of `b` and returns it with the
+// remaining bytes, returns ErrShortBuffer if `b` is truncated or ErrVarintOverflow if the value
+// overflows uint64.
+func ReadUvarint(b []byte) (uint64, []byte, error) {
+	val, n := binary.Uvarint(b)
+	switch {
+	case n == 0:
+		return 0, b, fmt.Errorf("%w: truncated varint", ErrShortBuffer)
+	case n < 0:
+		return 0, b, ErrVarintOverflow
+	default:
+		return val, b[n:], nil
+	}
+}
+
+// UvarintSize returns the number of bytes of the unsigned LEB128 varint encoding of `val`.
+func UvarintSize(val uint64) int {
+	return (bits.Len64(val|1) + 6) / 7
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// ZigZagEncode maps signed integers to unsigned integers so that numbers with a small absolute
+// value have a small encoded value: 0 => 0, -1 => 1, 1 => 2, -2 => 3, ...
+func ZigZagEncode(val int64) uint64 {
+	return uint64(val<<1) ^ uint64(val>>63)
+}
+
+// ZigZagDecode reverses ZigZagEncode.
+func ZigZagDecode(val uint64) int64 {
+	return int64(val>>1) ^ -int64(val&1)
+}
+
+// AppendVarint appends the zigzag varint encoding of `val` to `dst` and returns the extended
+// buffer.
+func AppendVarint(dst []byte, val int64) []byte {
+	return AppendUvarint(dst, ZigZagEncode(val))
+}
+
+// ReadVarint reads a zigzag varint from the beginning of `b` and returns it with the remaining
+// bytes, returns ErrShortBuffer if `b` is truncated or ErrVarintOverflow if the value overflows
+// int64.
+func ReadVarint(b []byte) (int64, []byte, error) {
+	val, rest, err := ReadUvarint(b)
+	return ZigZagDecode(val), rest, err
+}
+
+// VarintSize returns the number of bytes of the zigzag varint encoding of `val`.
+func VarintSize(val int64) int {
+	return UvarintSize(ZigZagEncode(val))
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// AppendPrefixVarint appends the prefix varint encoding of `val` to `dst` and returns the extended
+// buffer.
+//
+// A prefix varint stores the number of additional bytes as leading one bits of the first byte
+// followed by a zero bit, then the value in big endian, an integer that fits in 7*n bits takes n
+// bytes (n <= 8) and any larger one takes 9 bytes. Unlike LEB128, the length is known after
+// reading the first byte, and the encoding preserves the order of the values.
+func AppendPrefixVarint(dst []byte, val uint64) []byte {
+	size := PrefixVarintSize(val)
+	if size == MaxPrefixVarintLen {
+		return BigEndian.AppendU64(append(dst, 0xff), val)
+	}
+
+	extra := size - 1
+	for i := extra; i >= 0; i-- {
+		dst = append(dst, byte(val>>(8*i)))
+	}
+	dst[len(dst)-size] |= ^byte(0xff >> extra)
+	return dst
+}
+
+// ReadPrefixVarint reads a prefix varint from the beginning of `b` and returns it with the
+// remaining bytes, returns ErrShortBuffer if `b` is truncated.
+func ReadPrefixVarint(b []byte) (uint64, []byte, error) {
+	if len(b) == 0 {
+		return 0, b, fmt.Errorf("%w: truncated varint", ErrShortBuffer)
+	}
+
+	extra := bits.LeadingZeros8(^b[0])
+	if len(b) < extra+1 {
+		return 0, b, fmt.Errorf("%w: truncated varint", ErrShortBuffer)
+	}
+	if extra == MaxPrefixVarintLen-1 {
+		return BigEndian.ToU64(b[1:]), b[MaxPrefixVarintLen:], nil
+	}
+
+	val := uint64(b[0] & (0xff >> (extra + 1)))
+	for _, c := range b[1 : extra+1] {
+		val = val<<8 | uint64(c)
+	}
+	return val, b[extra+1:], nil
+}
+
+// PrefixVarintSize returns the number of bytes of the prefix varint encoding of `val`.
+func PrefixVarintSize(val uint64) int {
+	size := (bits.Len64(val|1) + 6) / 7
+	if size >= MaxPrefixVarintLen {
+		return MaxPrefixVarintLen
+	}
+	return size
+}
@@ -0,0 +1,104 @@
+package bytes_test
+
+import (
+	"bytes"
+	"cmp"
+	"math"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/daotl/guts/bytes"
+)
+
+var varintTestValues = []uint64{
+	0, 1, 0x7f, 0x80, 0xff, 0x3fff, 0x4000, 1<<21 - 1, 1 << 21, 1<<28 - 1, 1<<35 + 1,
+	1<<49 - 1, 1 << 49, 1<<56 - 1, 1 << 56, 1<<63 - 1, 1 << 63, math.MaxUint64,
+}
+
+func TestUvarint(t *testing.T) {
+	req := require.New(t)
+
+	for _, v := range varintTestValues {
+		enc := AppendUvarint([]byte{0xaa}, v)
+		req.Equal(byte(0xaa), enc[0])
+		req.Equal(UvarintSize(v), len(enc)-1, "%d", v)
+
+		dec, rest, err := ReadUvarint(append(enc[1:], 0xbb))
+		req.NoError(err)
+		req.Equal(v, dec)
+		req.Equal([]byte{0xbb}, rest)
+	}
+	req.Equal(1, UvarintSize(0))
+	req.Equal(MaxUvarintLen, UvarintSize(math.MaxUint64))
+}
+
+func TestVarint(t *testing.T) {
+	req := require.New(t)
+
+	for _, v := range []int64{0, -1, 1, -64, 63, -65, 64, math.MinInt64, math.MaxInt64} {
+		enc := AppendVarint(nil, v)
+		req.Equal(VarintSize(v), len(enc), "%d", v)
+
+		dec, rest, err := ReadVarint(enc)
+		req.NoError(err)
+		req.Equal(v, dec)
+		req.Empty(rest)
+	}
+	req.Equal(uint64(0), ZigZagEncode(0))
+	req.Equal(uint64(1), ZigZagEncode(-1))
+	req.Equal(uint64(2), ZigZagEncode(1))
+	req.Equal(1, VarintSize(-64))
+	req.Equal(2, VarintSize(64))
+}
+
+func TestPrefixVarint(t *testing.T) {
+	req := require.New(t)
+
+	for _, v := range varintTestValues {
+		enc := AppendPrefixVarint([]byte{0xaa}, v)
+		req.Equal(byte(0xaa), enc[0])
+		req.Equal(PrefixVarintSize(v), len(enc)-1, "%d", v)
+
+		dec, rest, err := ReadPrefixVarint(append(enc[1:], 0xbb))
+		req.NoError(err)
+		req.Equal(v, dec)
+		req.Equal([]byte{0xbb}, rest)
+	}
+	req.Equal([]byte{0x7f}, AppendPrefixVarint(nil, 0x7f))
+	req.Equal([]byte{0x80, 0x80}, AppendPrefixVarint(nil, 0x80))
+	req.Equal(8, PrefixVarintSize(1<<56-1))
+	req.Equal(MaxPrefixVarintLen, PrefixVarintSize(1<<56))
+
+	// Prefix varints preserve the order of the values.
+	err := quick.Check(func(a, b uint64) bool {
+		a >>= a % 64
+		b >>= b % 64
+		return bytes.Compare(AppendPrefixVarint(nil, a), AppendPrefixVarint(nil, b)) ==
+			cmp.Compare(a, b)
+	}, nil)
+	req.NoError(err)
+}
+
+func TestVarintErrors(t *testing.T) {
+	assr := assert.New(t)
+
+	_, _, err := ReadUvarint(nil)
+	assr.ErrorIs(err, ErrShortBuffer)
+	_, _, err = ReadUvarint([]byte{0x80, 0x80})
+	assr.ErrorIs(err, ErrShortBuffer)
+	_, _, err = ReadVarint([]byte{0xff})
+	assr.ErrorIs(err, ErrShortBuffer)
+	_, _, err = ReadUvarint(bytes.Repeat([]byte{0xff}, 11))
+	assr.ErrorIs(err, ErrVarintOverflow)
+
+	_, _, err = ReadPrefixVarint(nil)
+	assr.ErrorIs(err, ErrShortBuffer)
+	_, _, err = ReadPrefixVarint([]byte{0xc0, 0x00})
+	assr.ErrorIs(err, ErrShortBuffer)
+	_, rest, err := ReadPrefixVarint([]byte{0xff, 0, 0, 0, 0, 0, 0, 0})
+	assr.ErrorIs(err, ErrShortBuffer)
+	assr.Len(rest, 8)
+}
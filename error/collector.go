@@ -0,0 +1,202 @@
+package error
+
+import (
+	"fmt"
+	"hash/maphash"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	gsync "github.com/daotl/guts/sync"
+)
+
+// MultiError is an aggregate of errors returned by Collector and Group.
+//
+// Error returns all the messages on a single line suitable for logs, while %+v formats them over
+// multiple lines.
+type MultiError struct {
+	errs    []error
+	omitted int
+}
+
+var (
+	_ error         = &MultiError{}
+	_ fmt.Formatter = &MultiError{}
+)
+
+// Errors returns the stored errors.
+func (e *MultiError) Errors() []error {
+	return e.errs
+}
+
+// Omitted returns the number of errors omitted because of the limit.
+func (e *MultiError) Omitted() int {
+	return e.omitted
+}
+
+// Len returns the total number of errors including the omitted ones.
+func (e *MultiError) Len() int {
+	return len(e.errs) + e.omitted
+}
+
+// Unwrap returns the stored errors for errors.Is and errors.As.
+func (e *MultiError) Unwrap() []error {
+	return e.errs
+}
+
+// Error returns the message of the only error, or all the messages separated by "; " on a single
+// line, e.g. "3 errors: a; b; and 1 more".
+func (e *MultiError) Error() string {
+	if len(e.errs) == 1 && e.omitted == 0 {
+		return e.errs[0].Error()
+	}
+
+	var sb strings.Builder
+	sb.WriteString(strconv.Itoa(e.Len()) + " errors: ")
+	for i, err := range e.errs {
+		if i > 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(err.Error())
+	}
+	if e.omitted > 0 {
+		sb.WriteString("; and " + strconv.Itoa(e.omitted) + " more")
+	}
+	return sb.String()
+}
+
+// Format writes Error() for %s and %v, and the quoted Error() for %q. %+v writes one error per
+// line, each also with %+v.
+func (e *MultiError) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		var sb strings.Builder
+		sb.WriteString(strconv.Itoa(e.Len()) + " errors occurred:")
+		for _, err := range e.errs {
+			msg := fmt.Sprintf("%+v", err)
+			sb.WriteString("\n\t* " + strings.ReplaceAll(msg, "\n", "\n\t  "))
+		}
+		if e.omitted > 0 {
+			sb.WriteString("\n\tand " + strconv.Itoa(e.omitted) + " more")
+		}
+		io.WriteString(s, sb.String())
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		io.WriteString(s, e.Error())
+	}
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// Collector collects errors from multiple goroutines. Identical errors, that is, of the same type
+// with the same message, are only stored once, and at most `limit` errors are stored, the others
+// are counted and summarized as "and N more". To bound the memory usage, only the hashes of the
+// omitted errors are remembered to deduplicate them, up to MaxOmittedSeen distinct ones.
+//
+// The zero value is ready to use without limit.
+type Collector struct {
+	mu      gsync.Mutex
+	limit   int
+	errs    []error
+	seed    maphash.Seed
+	seen    map[uint64]struct{}
+	omitted int
+}
+
+// MaxOmittedSeen is the maximum number of distinct errors omitted by the limit that a Collector
+// remembers for deduplication, the duplicates of the others are counted as new errors.
+const MaxOmittedSeen = 1 << 14
+
+// NewCollector creates a Collector storing at most `limit` errors, no limit if `limit` <= 0.
+func NewCollector(limit int) *Collector {
+	return &Collector{limit: limit}
+}
+
+// Add adds `err` to the Collector, nil is ignored.
+func (c *Collector) Add(err error) {
+	if err == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.seen == nil {
+		c.seed = maphash.MakeSeed()
+		c.seen = make(map[uint64]struct{})
+	}
+
+	var h maphash.Hash
+	h.SetSeed(c.seed)
+	fmt.Fprintf(&h, "%T", err)
+	h.WriteByte(0)
+	h.WriteString(err.Error())
+	key := h.Sum64()
+	if _, ok := c.seen[key]; ok {
+		return
+	}
+
+	if c.limit > 0 && len(c.errs) >= c.limit {
+		c.omitted++
+		if len(c.seen) < c.limit+MaxOmittedSeen {
+			c.seen[key] = struct{}{}
+		}
+		return
+	}
+	c.seen[key] = struct{}{}
+	c.errs = append(c.errs, err)
+}
+
+// Len returns the number of distinct errors added including the omitted ones.
+func (c *Collector) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.errs) + c.omitted
+}
+
+// Err returns a *MultiError of the errors added so far, or nil if there is none.
+func (c *Collector) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.errs) == 0 {
+		return nil
+	}
+	return &MultiError{
+		errs:    append([]error(nil), c.errs...),
+		omitted: c.omitted,
+	}
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// Group runs functions in goroutines and collects their errors with a Collector, a panic is
+// collected as a *PanicError. Unlike errgroup.Group, it waits for all the functions and returns all
+// the errors.
+//
+// The zero value is ready to use without limit.
+type Group struct {
+	wg sync.WaitGroup
+	c  Collector
+}
+
+// NewGroup creates a Group storing at most `limit` errors, no limit if `limit` <= 0.
+func NewGroup(limit int) *Group {
+	return &Group{c: Collector{limit: limit}}
+}
+
+// Go runs `fn` in a new goroutine.
+func (g *Group) Go(fn func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		g.c.Add(CatchPanic(fn))
+	}()
+}
+
+// Wait waits for all the functions to return and returns a *MultiError of their errors, or nil if
+// there is none.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	return g.c.Err()
+}
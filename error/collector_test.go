@@ -0,0 +1,127 @@
+package error_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gerror "github.com/daotl/guts/error"
+)
+
+func TestCollector(t *testing.T) {
+	req := require.New(t)
+
+	var c gerror.Collector
+	req.NoError(c.Err())
+	c.Add(nil)
+	req.NoError(c.Err())
+
+	c.Add(io.EOF)
+	req.Equal("EOF", c.Err().Error())
+	req.ErrorIs(c.Err(), io.EOF)
+
+	// Identical errors are deduplicated, errors of different types with the same message aren't.
+	c.Add(io.EOF)
+	c.Add(errors.New("EOF"))
+	c.Add(fmt.Errorf("%w", io.EOF))
+	req.Equal(2, c.Len())
+	req.Equal("2 errors: EOF; EOF", c.Err().Error())
+}
+
+func TestCollectorLimit(t *testing.T) {
+	req := require.New(t)
+
+	c := gerror.NewCollector(2)
+	for i := 0; i < 5; i++ {
+		c.Add(fmt.Errorf("error %d", i))
+	}
+	c.Add(fmt.Errorf("error %d", 0))
+	c.Add(fmt.Errorf("error %d", 4))
+
+	err := c.Err()
+	req.Equal("5 errors: error 0; error 1; and 3 more", err.Error())
+	req.Equal(`"5 errors: error 0; error 1; and 3 more"`, fmt.Sprintf("%q", err))
+	req.Equal("5 errors occurred:\n\t* error 0\n\t* error 1\n\tand 3 more", fmt.Sprintf("%+v", err))
+
+	var me *gerror.MultiError
+	req.ErrorAs(err, &me)
+	req.Len(me.Errors(), 2)
+	req.Equal(3, me.Omitted())
+	req.Equal(5, me.Len())
+
+	// The returned error is a snapshot.
+	c.Add(io.EOF)
+	req.Len(me.Errors(), 2)
+	req.Equal(6, c.Len())
+
+	// Identical errors past the limit are deduplicated.
+	c = gerror.NewCollector(2)
+	c.Add(io.EOF)
+	c.Add(io.ErrUnexpectedEOF)
+	for i := 0; i < 1000; i++ {
+		c.Add(context.DeadlineExceeded)
+	}
+	req.Equal("3 errors: EOF; unexpected EOF; and 1 more", c.Err().Error())
+
+	// Only up to MaxOmittedSeen omitted errors are remembered.
+	c = gerror.NewCollector(1)
+	for i := 0; i <= gerror.MaxOmittedSeen+1; i++ {
+		c.Add(fmt.Errorf("error %d", i))
+	}
+	req.Equal(gerror.MaxOmittedSeen+2, c.Len())
+	c.Add(fmt.Errorf("error %d", gerror.MaxOmittedSeen))
+	req.Equal(gerror.MaxOmittedSeen+2, c.Len())
+	c.Add(fmt.Errorf("error %d", gerror.MaxOmittedSeen+1))
+	req.Equal(gerror.MaxOmittedSeen+3, c.Len())
+}
+
+func TestCollectorConcurrent(t *testing.T) {
+	assr := assert.New(t)
+
+	c := gerror.NewCollector(10)
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c.Add(fmt.Errorf("error %d", i%50))
+		}(i)
+	}
+	wg.Wait()
+
+	var me *gerror.MultiError
+	assr.ErrorAs(c.Err(), &me)
+	assr.Len(me.Errors(), 10)
+	assr.Equal(40, me.Omitted())
+}
+
+func TestGroup(t *testing.T) {
+	req := require.New(t)
+
+	var g gerror.Group
+	for i := 0; i < 3; i++ {
+		g.Go(func() error { return nil })
+	}
+	req.NoError(g.Wait())
+
+	g2 := gerror.NewGroup(0)
+	g2.Go(func() error { return io.EOF })
+	g2.Go(func() error { panic("boom") })
+	g2.Go(func() error { return nil })
+	err := g2.Wait()
+	req.ErrorIs(err, io.EOF)
+	var pe *gerror.PanicError
+	req.ErrorAs(err, &pe)
+	req.Equal("boom", pe.Value)
+
+	// Verbose format indents multi-line errors.
+	verbose := fmt.Sprintf("%+v", err)
+	req.Contains(verbose, "2 errors occurred:\n\t* ")
+	req.Contains(verbose, "\t* panic: boom\n\t  ")
+}
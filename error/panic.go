@@ -0,0 +1,95 @@
+package error
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+)
+
+// PanicError is an error converted from a recovered panic, carrying the original panic value and
+// the stack where the panic occurred.
+type PanicError struct {
+	Value any
+	Stack Stack
+}
+
+var (
+	_ error         = &PanicError{}
+	_ fmt.Formatter = &PanicError{}
+)
+
+// newPanicError creates a PanicError of the recovered value `v`, must be called from the deferred
+// function that recovered it.
+func newPanicError(v any) *PanicError {
+	if pe, ok := v.(*PanicError); ok {
+		return pe
+	}
+
+	stack := Callers(2)
+	// Drop the frames of the deferred calls up to the panic itself.
+	for i, pc := range stack {
+		if fn := runtime.FuncForPC(pc - 1); fn != nil && fn.Name() == "runtime.gopanic" {
+			stack = stack[i+1:]
+			break
+		}
+	}
+	return &PanicError{Value: v, Stack: stack}
+}
+
+// Error returns "panic: " followed by the panic value.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value converted by ToError, so a panic value that's an error, e.g. a
+// runtime.Error, can be checked by errors.Is and errors.As.
+func (e *PanicError) Unwrap() error {
+	return ToError(e.Value)
+}
+
+// Format writes Error() for %s and %v, and the quoted Error() for %q. %+v also writes the stack
+// on the following lines.
+func (e *PanicError) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		io.WriteString(s, e.Error()+"\n"+e.Stack.String())
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		io.WriteString(s, e.Error())
+	}
+}
+
+// Recover recovers a panic and stores it into `*err` as a *PanicError, must be deferred directly:
+//
+//	func f() (err error) {
+//		defer error.Recover(&err)
+//		...
+//	}
+//
+// `*err` is left untouched if there is no panic.
+func Recover(err *error) {
+	if v := recover(); v != nil {
+		*err = newPanicError(v)
+	}
+}
+
+// CatchPanic calls `fn` and returns its error, or a *PanicError if `fn` panics.
+func CatchPanic(fn func() error) (err error) {
+	defer Recover(&err)
+	return fn()
+}
+
+// SafeGo runs `fn` in a new goroutine, if `fn` panics, the panic is recovered and passed to
+// `onPanic` as a *PanicError instead of crashing the program. The panic is discarded if `onPanic`
+// is nil.
+func SafeGo(fn func(), onPanic func(err error)) {
+	go func() {
+		defer func() {
+			if v := recover(); v != nil && onPanic != nil {
+				onPanic(newPanicError(v))
+			}
+		}()
+		fn()
+	}()
+}
@@ -0,0 +1,91 @@
+package error_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gerror "github.com/daotl/guts/error"
+)
+
+func panicWith(v any) {
+	panic(v)
+}
+
+func TestRecover(t *testing.T) {
+	req := require.New(t)
+
+	f := func(v any) (err error) {
+		defer gerror.Recover(&err)
+		if v != nil {
+			panicWith(v)
+		}
+		return io.EOF
+	}
+
+	req.Equal(io.EOF, f(nil))
+
+	err := f("boom")
+	var pe *gerror.PanicError
+	req.ErrorAs(err, &pe)
+	req.Equal("boom", pe.Value)
+	req.Equal("panic: boom", err.Error())
+	// The stack starts at the panic site.
+	req.True(strings.HasPrefix(pe.Stack.String(), "github.com/daotl/guts/error_test.panicWith\n"),
+		pe.Stack.String())
+
+	err = f(io.ErrClosedPipe)
+	req.ErrorIs(err, io.ErrClosedPipe)
+}
+
+func TestCatchPanic(t *testing.T) {
+	req := require.New(t)
+
+	req.NoError(gerror.CatchPanic(func() error { return nil }))
+	req.Equal(io.EOF, gerror.CatchPanic(func() error { return io.EOF }))
+
+	// The original runtime.Error is preserved.
+	err := gerror.CatchPanic(func() error {
+		var m map[string]int
+		m["a"] = 1
+		return nil
+	})
+	var re runtime.Error
+	req.ErrorAs(err, &re)
+	req.Contains(err.Error(), "assignment to entry in nil map")
+
+	verbose := fmt.Sprintf("%+v", err)
+	req.True(strings.HasPrefix(verbose, "panic: assignment to entry in nil map\n"))
+	req.Contains(verbose, "panic_test.go:")
+	req.Equal(`"panic: x"`, fmt.Sprintf("%q", gerror.CatchPanic(func() error { panic("x") })))
+
+	// A re-panicked PanicError is kept as is.
+	inner := gerror.CatchPanic(func() error { panic("inner") })
+	req.Same(inner, gerror.CatchPanic(func() error { panic(inner) }))
+}
+
+func TestSafeGo(t *testing.T) {
+	assr := assert.New(t)
+
+	errCh := make(chan error, 1)
+	errBoom := errors.New("boom")
+	gerror.SafeGo(func() { panic(errBoom) }, func(err error) { errCh <- err })
+	err := <-errCh
+	assr.ErrorIs(err, errBoom)
+	var pe *gerror.PanicError
+	assr.ErrorAs(err, &pe)
+	assr.Equal(errBoom, pe.Value)
+
+	done := make(chan struct{})
+	gerror.SafeGo(func() {
+		defer close(done)
+		panic("discarded")
+	}, nil)
+	<-done
+}
@@ -0,0 +1,34 @@
+package error
+
+import (
+	"errors"
+)
+
+// permanentError marks an error as permanent, see Permanent.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks `err` as a permanent error that retrying won't fix, e.g. for the `retry` package
+// to stop retrying. It returns nil if `err` is nil, and the marked error still matches `err` by
+// errors.Is and errors.As.
+func Permanent(err error) error {
+	if err == nil || IsPermanent(err) {
+		return err
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether any error in the chain of `err` is marked by Permanent.
+func IsPermanent(err error) bool {
+	var pe *permanentError
+	return errors.As(err, &pe)
+}
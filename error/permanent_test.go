@@ -0,0 +1,26 @@
+package error_test
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	gerror "github.com/daotl/guts/error"
+)
+
+func TestPermanent(t *testing.T) {
+	assr := assert.New(t)
+
+	assr.Nil(gerror.Permanent(nil))
+	assr.False(gerror.IsPermanent(nil))
+	assr.False(gerror.IsPermanent(io.EOF))
+
+	err := gerror.Permanent(io.EOF)
+	assr.True(gerror.IsPermanent(err))
+	assr.True(gerror.IsPermanent(fmt.Errorf("read: %w", err)))
+	assr.ErrorIs(err, io.EOF)
+	assr.Equal("EOF", err.Error())
+	assr.Equal(err, gerror.Permanent(err))
+}
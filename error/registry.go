@@ -0,0 +1,311 @@
+package error
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	gbytes "github.com/daotl/guts/bytes"
+	gsync "github.com/daotl/guts/sync"
+)
+
+// wireVersion is the first byte of the binary encoding of errors.
+const wireVersion = 1
+
+// maxWireDepth is the maximum depth of causes when decoding an error.
+const maxWireDepth = 100
+
+// ErrInvalidWireError is returned when decoding invalid encoded errors.
+var ErrInvalidWireError = errors.New("invalid encoded error")
+
+var registry = struct {
+	mu     gsync.RWMutex
+	byCode map[string]error
+	byErr  map[error]string
+}{
+	byCode: make(map[string]error),
+	byErr:  make(map[error]string),
+}
+
+// Register registers a sentinel error with a stable code, so that it's restored as the same
+// sentinel when decoded by Decode or DecodeJSON in another process, and errors.Is works as usual.
+// Codes are usually prefixed with the package name, e.g. "suturesrv.not_running".
+//
+// Register is meant to be called in init functions, it panics if `code` is empty, `err` is nil or
+// not comparable, or either of them is already registered.
+func Register(code string, err error) {
+	if code == "" {
+		panic("error: Register with empty code")
+	}
+	if err == nil || !reflect.TypeOf(err).Comparable() {
+		panic("error: Register with nil or non-comparable error for code " + code)
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if _, ok := registry.byCode[code]; ok {
+		panic("error: Register called twice for code " + code)
+	}
+	if c, ok := registry.byErr[err]; ok {
+		panic(fmt.Sprintf("error: Register called twice for error %q, already registered as %s", err, c))
+	}
+	registry.byCode[code] = err
+	registry.byErr[err] = code
+}
+
+// Lookup returns the sentinel error registered with `code`.
+func Lookup(code string) (error, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	err, ok := registry.byCode[code]
+	return err, ok
+}
+
+// RegisteredCode returns the code of the first registered sentinel error in the chain of `err`.
+func RegisteredCode(err error) (string, bool) {
+	var code string
+	found := false
+	walk(err, func(e error) bool {
+		code, found = lookupCode(e)
+		return !found
+	})
+	return code, found
+}
+
+// lookupCode returns the code `err` itself is registered with.
+func lookupCode(err error) (string, bool) {
+	if !reflect.TypeOf(err).Comparable() {
+		return "", false
+	}
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	code, ok := registry.byErr[err]
+	return code, ok
+}
+
+// walk calls `fn` with `err` and the errors in its chain in the same order as errors.Is until `fn`
+// returns false, returns false if it's stopped.
+func walk(err error, fn func(error) bool) bool {
+	if err == nil {
+		return true
+	}
+	if !fn(err) {
+		return false
+	}
+	for _, e := range unwrapAll(err) {
+		if !walk(e, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// unwrapAll returns the errors wrapped by `err` by either Unwrap() error or Unwrap() []error.
+func unwrapAll(err error) []error {
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		if c := e.Unwrap(); c != nil {
+			return []error{c}
+		}
+	case interface{ Unwrap() []error }:
+		return e.Unwrap()
+	}
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// wireError is the stable encoded form of an error, which is one of:
+//   - A registered sentinel error with the code, and the message for human readers only.
+//   - An *Error with a code or fields, and its cause if any.
+//   - Any other error with its message and the causes it wraps.
+type wireError struct {
+	Code   string       `json:"code,omitempty"`
+	Msg    string       `json:"msg,omitempty"`
+	Fields []wireField  `json:"fields,omitempty"`
+	Causes []*wireError `json:"causes,omitempty"`
+}
+
+type wireField struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+func toWire(err error) *wireError {
+	if code, ok := lookupCode(err); ok {
+		return &wireError{Code: code, Msg: err.Error()}
+	}
+
+	if e, ok := err.(*Error); ok && (e.Code != "" || len(e.Fields) > 0) {
+		w := &wireError{Code: e.Code, Msg: e.Msg}
+		for _, f := range e.Fields {
+			w.Fields = append(w.Fields, wireField{Key: f.Key, Value: fmt.Sprint(f.Value)})
+		}
+		if e.Cause != nil {
+			w.Causes = []*wireError{toWire(e.Cause)}
+		}
+		return w
+	}
+
+	w := &wireError{Msg: err.Error()}
+	for _, c := range unwrapAll(err) {
+		if c != nil {
+			w.Causes = append(w.Causes, toWire(c))
+		}
+	}
+	return w
+}
+
+func fromWire(w *wireError) error {
+	if w.Code != "" && len(w.Fields) == 0 && len(w.Causes) == 0 {
+		if err, ok := Lookup(w.Code); ok {
+			return err
+		}
+	}
+
+	causes := make([]error, 0, len(w.Causes))
+	for _, c := range w.Causes {
+		if c != nil {
+			causes = append(causes, fromWire(c))
+		}
+	}
+
+	if w.Code != "" || len(w.Fields) > 0 {
+		e := &Error{Code: w.Code, Msg: w.Msg}
+		for _, f := range w.Fields {
+			e.Fields = append(e.Fields, Field{Key: f.Key, Value: f.Value})
+		}
+		if len(causes) > 0 {
+			e.Cause = causes[0]
+		}
+		return e
+	}
+	return &remoteError{msg: w.Msg, causes: causes}
+}
+
+// remoteError is a decoded error other than registered sentinels and *Error.
+type remoteError struct {
+	msg    string
+	causes []error
+}
+
+func (e *remoteError) Error() string {
+	return e.msg
+}
+
+func (e *remoteError) Unwrap() []error {
+	return e.causes
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// EncodeJSON encodes `err` and its chain into a stable JSON form, which can be decoded by
+// DecodeJSON in another process. Registered sentinel errors are encoded by their codes, *Error
+// with its code, message and fields with values formatted by %v, and any other errors by their
+// messages. Stacks and the original types of other errors are not preserved.
+func EncodeJSON(err error) ([]byte, error) {
+	if err == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(toWire(err))
+}
+
+// DecodeJSON decodes an error encoded by EncodeJSON into `out` like json.Unmarshal, registered
+// sentinel errors are restored as is, so errors.Is works as in the original process. The returned
+// error is the one occurred while decoding, `out` is untouched in that case.
+func DecodeJSON(data []byte, out *error) error {
+	var w *wireError
+	if err := json.Unmarshal(data, &w); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidWireError, err)
+	}
+	if w == nil {
+		*out = nil
+		return nil
+	}
+	if depth(w) > maxWireDepth {
+		return fmt.Errorf("%w: causes nested too deep", ErrInvalidWireError)
+	}
+	*out = fromWire(w)
+	return nil
+}
+
+func depth(w *wireError) int {
+	d := 0
+	for _, c := range w.Causes {
+		if c != nil {
+			d = max(d, depth(c))
+		}
+	}
+	return d + 1
+}
+
+// Encode encodes `err` and its chain into a stable compact binary form the same as EncodeJSON,
+// which can be decoded by Decode in another process. A nil error is encoded as empty bytes.
+func Encode(err error) []byte {
+	if err == nil {
+		return nil
+	}
+	w := gbytes.NewWriter([]byte{wireVersion}, gbytes.BigEndian)
+	writeWire(w, toWire(err))
+	return w.Bytes()
+}
+
+func writeWire(w *gbytes.Writer, we *wireError) {
+	w.LenString(we.Code)
+	w.LenString(we.Msg)
+	w.Uvarint(uint64(len(we.Fields)))
+	for _, f := range we.Fields {
+		w.LenString(f.Key)
+		w.LenString(f.Value)
+	}
+	w.Uvarint(uint64(len(we.Causes)))
+	for _, c := range we.Causes {
+		writeWire(w, c)
+	}
+}
+
+// Decode decodes an error encoded by Encode into `out` the same as DecodeJSON.
+func Decode(b []byte, out *error) error {
+	if len(b) == 0 {
+		*out = nil
+		return nil
+	}
+	if b[0] != wireVersion {
+		return fmt.Errorf("%w: unknown version %d", ErrInvalidWireError, b[0])
+	}
+
+	r := gbytes.NewReader(b[1:], gbytes.BigEndian)
+	w, err := readWire(r, 1)
+	if err == nil {
+		err = r.Err()
+	}
+	if err == nil && len(r.Remaining()) > 0 {
+		err = gbytes.ErrTrailingBytes
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidWireError, err)
+	}
+	*out = fromWire(w)
+	return nil
+}
+
+func readWire(r *gbytes.Reader, depth int) (*wireError, error) {
+	if depth > maxWireDepth {
+		return nil, errors.New("causes nested too deep")
+	}
+
+	w := &wireError{Code: r.LenString(), Msg: r.LenString()}
+	// A bogus count stops at the first read error.
+	for n := r.Uvarint(); n > 0 && r.Err() == nil; n-- {
+		w.Fields = append(w.Fields, wireField{Key: r.LenString(), Value: r.LenString()})
+	}
+	for n := r.Uvarint(); n > 0 && r.Err() == nil; n-- {
+		c, err := readWire(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		w.Causes = append(w.Causes, c)
+	}
+	return w, r.Err()
+}
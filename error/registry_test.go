@@ -0,0 +1,130 @@
+package error_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gerror "github.com/daotl/guts/error"
+	goprocesssrv "github.com/daotl/guts/service/goprocess"
+	suturesrv "github.com/daotl/guts/service/suture"
+)
+
+var errTestRegistered = errors.New("registered for test")
+
+func init() {
+	gerror.Register("error_test.registered", errTestRegistered)
+}
+
+func TestRegister(t *testing.T) {
+	assr := assert.New(t)
+
+	err, ok := gerror.Lookup("suturesrv.not_running")
+	assr.True(ok)
+	assr.Equal(suturesrv.ErrNotRunning, err)
+	_, ok = gerror.Lookup("unknown")
+	assr.False(ok)
+
+	code, ok := gerror.RegisteredCode(fmt.Errorf("stop: %w", goprocesssrv.ErrNotStarted))
+	assr.True(ok)
+	assr.Equal("goprocesssrv.not_started", code)
+	_, ok = gerror.RegisteredCode(io.EOF)
+	assr.False(ok)
+
+	assr.Panics(func() { gerror.Register("", io.EOF) })
+	assr.Panics(func() { gerror.Register("error_test.nil", nil) })
+	assr.Panics(func() { gerror.Register("error_test.registered", io.EOF) })
+	assr.Panics(func() { gerror.Register("error_test.registered2", errTestRegistered) })
+
+	// An *Error with a registered code matches the sentinel.
+	assr.ErrorIs(gerror.New("error_test.registered", "remote"), errTestRegistered)
+}
+
+func roundTrip(t *testing.T, err error) []error {
+	req := require.New(t)
+
+	jsonBytes, e := gerror.EncodeJSON(err)
+	req.NoError(e)
+	var fromJSON, fromBinary error
+	req.NoError(gerror.DecodeJSON(jsonBytes, &fromJSON))
+	req.NoError(gerror.Decode(gerror.Encode(err), &fromBinary))
+	return []error{fromJSON, fromBinary}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	assr := assert.New(t)
+
+	for _, err := range roundTrip(t, nil) {
+		assr.NoError(err)
+	}
+
+	// Registered sentinels are restored as is.
+	for _, err := range roundTrip(t, suturesrv.ErrAlreadyRunning) {
+		assr.Equal(suturesrv.ErrAlreadyRunning, err)
+	}
+
+	wrapped := fmt.Errorf("start: %w", suturesrv.ErrAlreadyRunning)
+	for _, err := range roundTrip(t, wrapped) {
+		assr.ErrorIs(err, suturesrv.ErrAlreadyRunning)
+		assr.Equal(wrapped.Error(), err.Error())
+	}
+
+	structured := gerror.Wrap(errors.Join(io.EOF, goprocesssrv.ErrNotStarted), "rpc", "call failed").
+		With("attempt", 3).WithStack()
+	for _, err := range roundTrip(t, structured) {
+		assr.ErrorIs(err, goprocesssrv.ErrNotStarted)
+		assr.ErrorIs(err, gerror.New("rpc", ""))
+		// Unregistered errors only keep their messages.
+		assr.NotErrorIs(err, io.EOF)
+		assr.Equal(structured.Error(), err.Error())
+
+		var e *gerror.Error
+		assr.ErrorAs(err, &e)
+		assr.Equal([]gerror.Field{{"attempt", "3"}}, e.Fields)
+		assr.Empty(e.Stack)
+	}
+}
+
+func TestEncodeFormat(t *testing.T) {
+	req := require.New(t)
+
+	err := gerror.Wrap(fmt.Errorf("dial: %w", suturesrv.ErrNotRunning), "rpc", "").With("k", "v")
+	jsonBytes, e := gerror.EncodeJSON(err)
+	req.NoError(e)
+	req.JSONEq(`{"code":"rpc","fields":[{"key":"k","value":"v"}],"causes":[
+		{"msg":"dial: not running","causes":[{"code":"suturesrv.not_running","msg":"not running"}]}]}`,
+		string(jsonBytes))
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	assr := assert.New(t)
+
+	decoded := io.EOF
+	err := gerror.DecodeJSON([]byte(`{"code":1}`), &decoded)
+	assr.ErrorIs(err, gerror.ErrInvalidWireError)
+
+	b := gerror.Encode(suturesrv.ErrNotRunning)
+	err = gerror.Decode(b[:len(b)-1], &decoded)
+	assr.ErrorIs(err, gerror.ErrInvalidWireError)
+	err = gerror.Decode(append(b, 0), &decoded)
+	assr.ErrorIs(err, gerror.ErrInvalidWireError)
+	err = gerror.Decode(append([]byte{0xff}, b[1:]...), &decoded)
+	assr.ErrorIs(err, gerror.ErrInvalidWireError)
+
+	var deep error = io.EOF
+	for i := 0; i < 200; i++ {
+		deep = fmt.Errorf("%d: %w", i, deep)
+	}
+	err = gerror.Decode(gerror.Encode(deep), &decoded)
+	assr.ErrorIs(err, gerror.ErrInvalidWireError)
+	jsonBytes, err := gerror.EncodeJSON(deep)
+	assr.NoError(err)
+	err = gerror.DecodeJSON(jsonBytes, &decoded)
+	assr.ErrorIs(err, gerror.ErrInvalidWireError)
+	// `out` is untouched on failure.
+	assr.Equal(io.EOF, decoded)
+}
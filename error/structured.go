@@ -0,0 +1,239 @@
+package error
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Field is a key/value pair of context attached to an Error.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// Stack is a captured call stack.
+type Stack []uintptr
+
+// Callers captures the call stack of the caller of Callers, `skip` is the number of additional
+// frames to skip.
+func Callers(skip int) Stack {
+	var pcs [32]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	return Stack(pcs[:n:n])
+}
+
+// String returns the stack with one "function\n\tfile:line" entry per line.
+func (s Stack) String() string {
+	var sb strings.Builder
+	frames := runtime.CallersFrames(s)
+	for {
+		f, more := frames.Next()
+		if f.Function != "" || f.File != "" {
+			if sb.Len() > 0 {
+				sb.WriteByte('\n')
+			}
+			sb.WriteString(f.Function)
+			sb.WriteString("\n\t")
+			sb.WriteString(f.File)
+			sb.WriteByte(':')
+			sb.WriteString(strconv.Itoa(f.Line))
+		}
+		if !more {
+			return sb.String()
+		}
+	}
+}
+
+/////////////////////////////////////////////////////////////////////////////
+
+// Error is a structured error carrying a machine-readable code, a message, key/value fields, an
+// optional captured stack and a wrapped cause.
+//
+// Error works with errors.Is, errors.As and errors.Join: it unwraps to its cause and matches
+// another *Error with the same non-empty code, so an Error declared as a sentinel matches the
+// copies derived from it by With or WithStack. It's also a zapcore.ObjectMarshaler, so use
+// zap.Object or zap.Any to log it with all the structure.
+//
+// The methods never modify the receiver but return a modified copy, so they're safe to call on
+// sentinel errors.
+type Error struct {
+	Code   string
+	Msg    string
+	Fields []Field
+	Stack  Stack
+	Cause  error
+}
+
+var (
+	_ error                   = &Error{}
+	_ fmt.Formatter           = &Error{}
+	_ zapcore.ObjectMarshaler = &Error{}
+)
+
+// New creates an Error with `code` and `msg`.
+func New(code, msg string) *Error {
+	return &Error{Code: code, Msg: msg}
+}
+
+// Wrap creates an Error with `code` and `msg` wrapping `cause`.
+func Wrap(cause error, code, msg string) *Error {
+	return &Error{Code: code, Msg: msg, Cause: cause}
+}
+
+// CodeOf returns the code of the first Error with a non-empty code in the chain of `err`, or an
+// empty string if there is none.
+func CodeOf(err error) string {
+	var e *Error
+	for errors.As(err, &e) {
+		if e.Code != "" {
+			return e.Code
+		}
+		err = e.Cause
+	}
+	return ""
+}
+
+// With returns a copy of the Error with key/value fields `kv` appended, e.g.
+// `err.With("user", id, "retries", 3)`. A key that's not a string is formatted with %v, and a
+// missing value of the last key is nil.
+func (e *Error) With(kv ...any) *Error {
+	c := *e
+	c.Fields = make([]Field, len(e.Fields), len(e.Fields)+(len(kv)+1)/2)
+	copy(c.Fields, e.Fields)
+	for i := 0; i < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok {
+			key = fmt.Sprint(kv[i])
+		}
+		var val any
+		if i+1 < len(kv) {
+			val = kv[i+1]
+		}
+		c.Fields = append(c.Fields, Field{Key: key, Value: val})
+	}
+	return &c
+}
+
+// WithStack returns a copy of the Error with the call stack of the caller captured.
+func (e *Error) WithStack() *Error {
+	c := *e
+	c.Stack = Callers(1)
+	return &c
+}
+
+// Error returns the message, or the code if the message is empty, followed by ": " and the message
+// of the cause if there is one.
+func (e *Error) Error() string {
+	msg := e.Msg
+	if msg == "" {
+		msg = e.Code
+	}
+	if e.Cause == nil {
+		return msg
+	}
+	if msg == "" {
+		return e.Cause.Error()
+	}
+	return msg + ": " + e.Cause.Error()
+}
+
+// Unwrap returns the cause.
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether `target` is an *Error with the same non-empty code, or the sentinel error
+// registered with the code.
+func (e *Error) Is(target error) bool {
+	if e.Code == "" {
+		return false
+	}
+	if t, ok := target.(*Error); ok {
+		return e.Code == t.Code
+	}
+	s, ok := Lookup(e.Code)
+	return ok && s == target
+}
+
+// Format writes Error() for %s and %v, and the quoted Error() for %q. %+v writes all the
+// structure over multiple lines: the code, message, fields, the stack if captured, then the cause
+// also with %+v.
+func (e *Error) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		e.writeVerbose(s)
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		io.WriteString(s, e.Error())
+	}
+}
+
+func (e *Error) writeVerbose(w io.Writer) {
+	var sb strings.Builder
+	if e.Code != "" {
+		sb.WriteString("[" + e.Code + "]")
+	}
+	if e.Msg != "" {
+		if sb.Len() > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(e.Msg)
+	}
+	for i, f := range e.Fields {
+		switch {
+		case i == 0 && sb.Len() > 0:
+			sb.WriteString(" {")
+		case i == 0:
+			sb.WriteString("{")
+		default:
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "%s=%v", f.Key, f.Value)
+		if i == len(e.Fields)-1 {
+			sb.WriteByte('}')
+		}
+	}
+	if len(e.Stack) > 0 {
+		sb.WriteByte('\n')
+		sb.WriteString(e.Stack.String())
+	}
+	if e.Cause != nil {
+		fmt.Fprintf(&sb, "\ncaused by: %+v", e.Cause)
+	}
+	io.WriteString(w, sb.String())
+}
+
+// MarshalLogObject implements the zapcore.ObjectMarshaler interface. The code, message, fields,
+// the cause and the stack are added as separate keys if they're not empty.
+func (e *Error) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if e.Code != "" {
+		enc.AddString("code", e.Code)
+	}
+	if e.Msg != "" {
+		enc.AddString("msg", e.Msg)
+	}
+	for _, f := range e.Fields {
+		zap.Any(f.Key, f.Value).AddTo(enc)
+	}
+	if e.Cause != nil {
+		if c, ok := e.Cause.(*Error); ok {
+			if err := enc.AddObject("cause", c); err != nil {
+				return err
+			}
+		} else {
+			enc.AddString("cause", e.Cause.Error())
+		}
+	}
+	if len(e.Stack) > 0 {
+		enc.AddString("stack", e.Stack.String())
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package error_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	gerror "github.com/daotl/guts/error"
+)
+
+var errTestNotFound = gerror.New("not_found", "resource not found")
+
+func TestErrorMessage(t *testing.T) {
+	assr := assert.New(t)
+
+	assr.Equal("resource not found", errTestNotFound.Error())
+	assr.Equal("not_found", gerror.New("not_found", "").Error())
+	assr.Equal("load config: unexpected EOF",
+		gerror.Wrap(io.ErrUnexpectedEOF, "config", "load config").Error())
+	assr.Equal("unexpected EOF", gerror.Wrap(io.ErrUnexpectedEOF, "", "").Error())
+}
+
+func TestErrorIsAs(t *testing.T) {
+	assr := assert.New(t)
+
+	err := errTestNotFound.With("id", 42).WithStack()
+	assr.ErrorIs(err, errTestNotFound)
+	// With and WithStack don't modify the sentinel.
+	assr.Empty(errTestNotFound.Fields)
+	assr.Empty(errTestNotFound.Stack)
+
+	wrapped := fmt.Errorf("handler: %w", gerror.Wrap(err, "internal", "lookup failed"))
+	assr.ErrorIs(wrapped, errTestNotFound)
+	assr.False(errors.Is(wrapped, gerror.New("other", "resource not found")))
+	assr.False(errors.Is(gerror.New("", "a"), gerror.New("", "a")))
+
+	var e *gerror.Error
+	assr.ErrorAs(wrapped, &e)
+	assr.Equal("internal", e.Code)
+	assr.Equal("internal", gerror.CodeOf(wrapped))
+	assr.Equal("not_found", gerror.CodeOf(fmt.Errorf("%w", gerror.Wrap(err, "", "no code"))))
+	assr.Equal("", gerror.CodeOf(io.EOF))
+
+	joined := errors.Join(io.EOF, err)
+	assr.ErrorIs(joined, errTestNotFound)
+	assr.ErrorIs(joined, io.EOF)
+
+	assr.ErrorIs(gerror.Wrap(io.EOF, "eof", ""), io.EOF)
+}
+
+func TestErrorWith(t *testing.T) {
+	assr := assert.New(t)
+
+	err := errTestNotFound.With("id", 42, 1, "one", "dangling")
+	assr.Equal([]gerror.Field{{"id", 42}, {"1", "one"}, {"dangling", nil}}, err.Fields)
+
+	err2 := err.With("more", true)
+	assr.Len(err.Fields, 3)
+	assr.Len(err2.Fields, 4)
+}
+
+func TestErrorFormat(t *testing.T) {
+	assr := assert.New(t)
+
+	err := gerror.Wrap(errTestNotFound.With("id", 42), "internal", "lookup failed").With("user", "bob")
+	assr.Equal("lookup failed: resource not found", fmt.Sprintf("%v", err))
+	assr.Equal(`"lookup failed: resource not found"`, fmt.Sprintf("%q", err))
+	assr.Equal("[internal] lookup failed {user=bob}\ncaused by: [not_found] resource not found {id=42}",
+		fmt.Sprintf("%+v", err))
+
+	stacked := errTestNotFound.WithStack()
+	verbose := fmt.Sprintf("%+v", stacked)
+	assr.True(strings.HasPrefix(verbose, "[not_found] resource not found\n"))
+	assr.Contains(verbose, "error_test.TestErrorFormat")
+	assr.Contains(verbose, "structured_test.go:")
+}
+
+func TestErrorMarshalLogObject(t *testing.T) {
+	req := require.New(t)
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(core)
+
+	err := gerror.Wrap(errTestNotFound.With("id", 42), "internal", "lookup failed").With("user", "bob")
+	logger.Error("request failed", zap.Any("error", err))
+
+	req.Equal(1, logs.Len())
+	req.Equal(map[string]any{
+		"error": map[string]any{
+			"code": "internal",
+			"msg":  "lookup failed",
+			"user": "bob",
+			"cause": map[string]any{
+				"code": "not_found",
+				"msg":  "resource not found",
+				"id":   int64(42),
+			},
+		},
+	}, logs.All()[0].ContextMap())
+
+	logger.Error("request failed", zap.Object("error", gerror.Wrap(io.EOF, "", "read").WithStack()))
+	fields := logs.All()[1].ContextMap()["error"].(map[string]any)
+	req.Equal("EOF", fields["cause"])
+	req.Contains(fields["stack"], "TestErrorMarshalLogObject")
+}
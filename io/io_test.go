@@ -1,6 +1,16 @@
 package io_test
 
-import "io"
+import (
+	"io"
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+// TestMain fails the tests if any goroutine is leaked.
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
 
 const TestStr = "Hello, World!"
 
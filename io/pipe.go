@@ -0,0 +1,162 @@
+package io
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	gsync "github.com/daotl/guts/sync"
+)
+
+// pipe is an in-memory pipe like io.Pipe, which additionally keeps track of whether the reader
+// has consumed all the written data so that writers can flush, and optionally buffers up to
+// `size` bytes to decouple the writer from the reader.
+//
+// If `size` is 0, the pipe is synchronous like io.Pipe: Write() blocks until all the data is read.
+// Otherwise Write() only blocks while the buffer is full.
+type pipe struct {
+	wrMu    gsync.Mutex // serializes Write()
+	mu      gsync.Mutex
+	cond    *sync.Cond
+	buf     bytes.Buffer
+	size    int
+	nread   int64 // the total number of bytes read
+	waiting bool  // the reader is blocked in Read() waiting for data
+	werr    error // set when the write end is closed
+	rerr    error // set when the read end is closed
+}
+
+func newPipe(size int) *pipe {
+	p := &pipe{size: size}
+	p.cond = sync.NewCond(&p.mu)
+	return p
+}
+
+// Write writes `b` to the pipe, it returns the error the read end is closed with, or
+// io.ErrClosedPipe if the write end is closed.
+func (p *pipe) Write(b []byte) (n int, err error) {
+	// Concurrent writes are gated one after another like io.Pipe, so that their data isn't
+	// interleaved, and in the synchronous mode the buffer only holds the data of this write.
+	p.wrMu.Lock()
+	defer p.wrMu.Unlock()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for len(b) > 0 {
+		for p.rerr == nil && p.werr == nil && p.size > 0 && p.buf.Len() >= p.size {
+			p.cond.Wait()
+		}
+		if err = p.writeCloseError(); err != nil {
+			return n, err
+		}
+		k := len(b)
+		if p.size > 0 {
+			k = min(k, p.size-p.buf.Len())
+		}
+		p.buf.Write(b[:k])
+		n += k
+		b = b[k:]
+		p.cond.Broadcast()
+	}
+
+	if p.size == 0 {
+		// Wait for the reader to consume the data like io.Pipe.
+		end := p.nread + int64(p.buf.Len())
+		for p.rerr == nil && p.werr == nil && p.nread < end {
+			p.cond.Wait()
+		}
+		if unread := end - p.nread; unread > 0 {
+			// The unread data is discarded and not counted as written.
+			n -= int(unread)
+			p.buf.Reset()
+			return n, p.writeCloseError()
+		}
+	}
+	return n, nil
+}
+
+// Flush blocks until all the data written so far is consumed by the reader, and the reader is
+// waiting for more data in Read(). It returns the error the read end is closed with if it's
+// closed before that, or io.ErrClosedPipe if the write end is closed.
+func (p *pipe) Flush() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for p.rerr == nil && p.werr == nil && (p.buf.Len() > 0 || !p.waiting) {
+		p.cond.Wait()
+	}
+	return p.writeCloseError()
+}
+
+// Read reads data from the pipe, it returns the error the write end is closed with, or io.EOF if
+// it's closed with a nil error, once all the buffered data is read.
+func (p *pipe) Read(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for p.buf.Len() == 0 && p.werr == nil && p.rerr == nil {
+		p.waiting = true
+		p.cond.Broadcast()
+		p.cond.Wait()
+	}
+	p.waiting = false
+	if p.rerr != nil {
+		return 0, io.ErrClosedPipe
+	}
+	if p.buf.Len() == 0 {
+		return 0, p.werr
+	}
+	n, _ := p.buf.Read(b)
+	p.nread += int64(n)
+	p.cond.Broadcast()
+	return n, nil
+}
+
+// CloseWrite closes the write end of the pipe, the following reads return `err` once all the
+// buffered data is read, or io.EOF if `err` is nil.
+func (p *pipe) CloseWrite(err error) error {
+	if err == nil {
+		err = io.EOF
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.werr == nil {
+		p.werr = err
+	}
+	p.cond.Broadcast()
+	return nil
+}
+
+// Abort closes the write end of the pipe and discards the buffered data, so that the following
+// reads return `err` right away.
+func (p *pipe) Abort(err error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.werr = err
+	p.buf.Reset()
+	p.cond.Broadcast()
+	return nil
+}
+
+// CloseRead closes the read end of the pipe, the following writes return `err`, or
+// io.ErrClosedPipe if `err` is nil.
+func (p *pipe) CloseRead(err error) error {
+	if err == nil {
+		err = io.ErrClosedPipe
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.rerr == nil {
+		p.rerr = err
+	}
+	p.cond.Broadcast()
+	return nil
+}
+
+// writeCloseError returns the error for writing to the pipe, or nil if it's writable.
+func (p *pipe) writeCloseError() error {
+	if p.werr != nil {
+		return io.ErrClosedPipe
+	}
+	return p.rerr
+}
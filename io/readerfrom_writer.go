@@ -1,51 +1,135 @@
 package io
 
 import (
+	"context"
 	"io"
-	"sync"
 )
 
-// ReaderFromWriter is a type that wraps an io.ReaderFrom and implements ReaderFromWriter using io.Pipe.
-// Due to the asynchronous nature of io.Pipe, Write() will only be guaranteed to be visible after a call
-// to Sync() or Close().
+// ReaderFromWriter is a type that wraps an io.ReaderFrom and implements ReaderFromWriter using an
+// in-memory pipe like io.Pipe. Due to the asynchronous nature of the pipe, Write() will only be
+// guaranteed to be visible after a call to Flush(), Sync() or Close().
 type ReaderFromWriter struct {
 	io.ReaderFrom
-	*io.PipeWriter
 
-	pipeR *io.PipeReader
-	wg    sync.WaitGroup
+	ctx  context.Context
+	pipe *pipe
+	done chan struct{}
+	err  error
 }
 
 var _ ReadFromWriteCloser = (*ReaderFromWriter)(nil)
 
 // NewReaderFromWriter creates a new ReaderFromWriter.
 func NewReaderFromWriter(readerFrom io.ReaderFrom) *ReaderFromWriter {
-	pipeR, pipeW := io.Pipe()
+	return NewReaderFromWriterWithContext(context.Background(), readerFrom)
+}
+
+// NewReaderFromWriterWithContext creates a new ReaderFromWriter which aborts the pipe with
+// ctx.Err() when `ctx` is done, so that both Write() and the reads of the wrapped ReadFrom() fail
+// with ctx.Err() instead of blocking forever. The data not read yet is discarded.
+func NewReaderFromWriterWithContext(ctx context.Context, readerFrom io.ReaderFrom) *ReaderFromWriter {
+	return NewBufferedReaderFromWriterWithContext(ctx, readerFrom, 0)
+}
+
+// NewBufferedReaderFromWriter creates a new ReaderFromWriter which buffers up to `size` bytes, so
+// that Write() only blocks while the buffer is full instead of until the wrapped ReadFrom() reads
+// all the data. The ReaderFromWriter is unbuffered if `size` is 0.
+func NewBufferedReaderFromWriter(readerFrom io.ReaderFrom, size int) *ReaderFromWriter {
+	return NewBufferedReaderFromWriterWithContext(context.Background(), readerFrom, size)
+}
+
+// NewBufferedReaderFromWriterWithContext is like NewBufferedReaderFromWriter but aborts the pipe
+// with ctx.Err() when `ctx` is done like NewReaderFromWriterWithContext.
+func NewBufferedReaderFromWriterWithContext(
+	ctx context.Context,
+	readerFrom io.ReaderFrom,
+	size int,
+) *ReaderFromWriter {
+	if size < 0 {
+		panic("io: negative buffer size")
+	}
 	rfw := &ReaderFromWriter{
 		ReaderFrom: readerFrom,
-		pipeR:      pipeR,
-		PipeWriter: pipeW,
+		ctx:        ctx,
+		pipe:       newPipe(size),
+		done:       make(chan struct{}),
 	}
-	rfw.wg.Add(1)
 	go rfw.readFromPipe()
+	if ctx.Done() != nil {
+		go abortPipeOnDone(ctx, rfw.done, rfw.pipe.Abort)
+	}
 	return rfw
 }
 
 // readFromPipe reads data from the pipe and writes it to the readerFrom.
 func (rfw *ReaderFromWriter) readFromPipe() {
-	defer rfw.wg.Done()
-	_, err := rfw.ReadFrom(rfw.pipeR)
-	rfw.CloseWithError(err)
+	defer close(rfw.done)
+	_, rfw.err = rfw.ReadFrom(rfw.pipe)
+	// Fail the following writes with the error of ReadFrom, or io.ErrClosedPipe if it's nil.
+	rfw.pipe.CloseRead(rfw.err)
+}
+
+// Write writes `p` to the pipe, returns ctx.Err() if the pipe is aborted.
+func (rfw *ReaderFromWriter) Write(p []byte) (int, error) {
+	n, err := rfw.pipe.Write(p)
+	return n, pipeError(rfw.ctx, err)
+}
+
+// Flush waits until all the data written so far is consumed by the wrapped ReadFrom(), i.e. it
+// has read all the data and is waiting for more. It returns the same error as Write() would if
+// ReadFrom() returns or the pipe is closed before that.
+func (rfw *ReaderFromWriter) Flush() error {
+	return pipeError(rfw.ctx, rfw.pipe.Flush())
 }
 
 // Sync waits for the readFromPipe goroutine to finish.
 func (rfw *ReaderFromWriter) Sync() {
-	rfw.wg.Wait()
+	<-rfw.done
+}
+
+// Done returns a channel that's closed when the readFromPipe goroutine finishes.
+func (rfw *ReaderFromWriter) Done() <-chan struct{} {
+	return rfw.done
+}
+
+// Err returns the error returned by the wrapped ReadFrom(), or nil if it hasn't returned yet.
+func (rfw *ReaderFromWriter) Err() error {
+	select {
+	case <-rfw.done:
+		return rfw.err
+	default:
+		return nil
+	}
 }
 
 // Close closes the pipe writer and waits for the readFromPipe goroutine to finish.
 func (rfw *ReaderFromWriter) Close() error {
-	err := rfw.PipeWriter.Close()
+	err := rfw.CloseWithError(nil)
 	rfw.Sync()
 	return err
 }
+
+// CloseWithError closes the pipe writer without waiting like io.PipeWriter.CloseWithError, the
+// wrapped ReadFrom() reads `err` after all the written data, or io.EOF if `err` is nil.
+func (rfw *ReaderFromWriter) CloseWithError(err error) error {
+	return rfw.pipe.CloseWrite(err)
+}
+
+// abortPipeOnDone aborts the pipe by closing the end used by the background goroutine with
+// ctx.Err() when `ctx` is done before `finished` is closed.
+func abortPipeOnDone(ctx context.Context, finished <-chan struct{}, closeWithError func(error) error) {
+	select {
+	case <-ctx.Done():
+		closeWithError(ctx.Err())
+	case <-finished:
+	}
+}
+
+// pipeError returns ctx.Err() instead of io.ErrClosedPipe returned by the end of the pipe used by
+// the caller if the pipe is aborted by abortPipeOnDone.
+func pipeError(ctx context.Context, err error) error {
+	if err == io.ErrClosedPipe && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
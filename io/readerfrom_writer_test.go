@@ -1,8 +1,13 @@
 package io_test
 
 import (
+	"bytes"
+	"context"
 	"io"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	gio "github.com/daotl/guts/io"
 	"github.com/stretchr/testify/assert"
@@ -72,4 +77,202 @@ func TestReaderFromWriter(t *testing.T) {
 		t,
 		func(rf io.ReaderFrom) gio.ReadFromWriteCloser { return gio.NewReaderFromWriter(rf) },
 	)
+
+	// CloseWithError doesn't wait for ReadFrom, which reads the error.
+	req := require.New(t)
+	brf := &blockingReaderFrom{release: make(chan struct{})}
+	rfw := gio.NewReaderFromWriter(brf)
+	req.NoError(rfw.CloseWithError(io.ErrUnexpectedEOF))
+	close(brf.release)
+	rfw.Sync()
+	req.ErrorIs(rfw.Err(), io.ErrUnexpectedEOF)
+}
+
+// blockingReaderFrom waits for `release` to be closed before reading like ExampleReaderFrom.
+type blockingReaderFrom struct {
+	ExampleReaderFrom
+	release chan struct{}
+}
+
+func (brf *blockingReaderFrom) ReadFrom(r io.Reader) (int64, error) {
+	<-brf.release
+	return brf.ExampleReaderFrom.ReadFrom(r)
+}
+
+// TestReaderFromWriterWithContext tests that canceling the context aborts the pipe.
+func TestReaderFromWriterWithContext(t *testing.T) {
+	req := require.New(t)
+
+	testReaderFromWriter(
+		t,
+		func(rf io.ReaderFrom) gio.ReadFromWriteCloser {
+			return gio.NewReaderFromWriterWithContext(context.Background(), rf)
+		},
+	)
+
+	// A write blocked by a slow ReadFrom fails when the context is canceled.
+	ctx, cancel := context.WithCancel(context.Background())
+	brf := &blockingReaderFrom{release: make(chan struct{})}
+	rfw := gio.NewReaderFromWriterWithContext(ctx, brf)
+	req.NoError(rfw.Err())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, err := rfw.Write(TestBin)
+	req.ErrorIs(err, context.Canceled)
+
+	// The final error of ReadFrom is reported.
+	close(brf.release)
+	<-rfw.Done()
+	req.ErrorIs(rfw.Err(), context.Canceled)
+	req.Empty(brf.data)
+	_, err = rfw.Write(TestBin)
+	req.ErrorIs(err, context.Canceled)
+	req.NoError(rfw.Close())
+
+	// The context is ignored once ReadFrom returns.
+	ctx, cancel = context.WithCancel(context.Background())
+	erf := &ExampleReaderFrom{}
+	rfw = gio.NewReaderFromWriterWithContext(ctx, erf)
+	_, err = rfw.Write(TestBin)
+	req.NoError(err)
+	req.NoError(rfw.Close())
+	cancel()
+	req.NoError(rfw.Err())
+	req.Equal(TestStr, string(erf.data))
+}
+
+// TestReaderFromWriterFlush tests that Flush waits for ReadFrom to consume the written data.
+func TestReaderFromWriterFlush(t *testing.T) {
+	for _, size := range []int{0, 4, 1024} {
+		req := require.New(t)
+
+		erf := &ExampleReaderFrom{}
+		rfw := gio.NewBufferedReaderFromWriter(erf, size)
+		req.NoError(rfw.Flush())
+		req.Empty(erf.data)
+
+		var expected string
+		for _, chunk := range TestStrChunks {
+			_, err := rfw.Write([]byte(chunk))
+			req.NoError(err)
+			req.NoError(rfw.Flush())
+			expected += chunk
+			req.Equal(expected, string(erf.data), "size=%d", size)
+		}
+
+		req.NoError(rfw.Close())
+		req.ErrorIs(rfw.Flush(), io.ErrClosedPipe)
+		req.Equal(TestStr, string(erf.data))
+	}
+}
+
+// TestBufferedReaderFromWriter tests the ReaderFromWriter implementation with an internal buffer.
+func TestBufferedReaderFromWriter(t *testing.T) {
+	req := require.New(t)
+
+	testReaderFromWriter(
+		t,
+		func(rf io.ReaderFrom) gio.ReadFromWriteCloser { return gio.NewBufferedReaderFromWriter(rf, 4) },
+	)
+
+	// Write doesn't block on a slow ReadFrom until the buffer is full.
+	brf := &blockingReaderFrom{release: make(chan struct{})}
+	rfw := gio.NewBufferedReaderFromWriter(brf, len(TestBin))
+	n, err := rfw.Write(TestBin)
+	req.NoError(err)
+	req.Equal(len(TestBin), n)
+	close(brf.release)
+	req.NoError(rfw.Flush())
+	req.Equal(TestStr, string(brf.data))
+	req.NoError(rfw.Close())
+
+	// Writes blocked by the full buffer fail when the context is canceled.
+	ctx, cancel := context.WithCancel(context.Background())
+	brf = &blockingReaderFrom{release: make(chan struct{})}
+	rfw = gio.NewBufferedReaderFromWriterWithContext(ctx, brf, 4)
+	time.AfterFunc(10*time.Millisecond, cancel)
+	n, err = rfw.Write(TestBin)
+	req.ErrorIs(err, context.Canceled)
+	req.Equal(4, n)
+	req.ErrorIs(rfw.Flush(), context.Canceled)
+	close(brf.release)
+	req.NoError(rfw.Close())
+
+	// Canceling the context discards the buffered data.
+	ctx, cancel = context.WithCancel(context.Background())
+	brf = &blockingReaderFrom{release: make(chan struct{})}
+	rfw = gio.NewBufferedReaderFromWriterWithContext(ctx, brf, 1<<20)
+	n, err = rfw.Write(bytes.Repeat([]byte("a"), 1000))
+	req.NoError(err)
+	req.Equal(1000, n)
+	cancel()
+	req.ErrorIs(rfw.Flush(), context.Canceled)
+	close(brf.release)
+	rfw.Sync()
+	req.ErrorIs(rfw.Err(), context.Canceled)
+	req.Empty(brf.data)
+	req.NoError(rfw.Close())
+
+	// Write fails with the error of ReadFrom.
+	rfw = gio.NewBufferedReaderFromWriter(readerFromFunc(func(r io.Reader) (int64, error) {
+		return 0, io.ErrUnexpectedEOF
+	}), 4)
+	rfw.Sync()
+	_, err = rfw.Write(TestBin)
+	req.ErrorIs(err, io.ErrUnexpectedEOF)
+	req.ErrorIs(rfw.Flush(), io.ErrUnexpectedEOF)
+	req.NoError(rfw.Close())
+}
+
+// readerFromFunc is an adapter to allow the use of ordinary functions as io.ReaderFrom.
+type readerFromFunc func(r io.Reader) (int64, error)
+
+func (f readerFromFunc) ReadFrom(r io.Reader) (int64, error) {
+	return f(r)
+}
+
+// TestReaderFromWriterConcurrentWrites tests that concurrent writes aren't interleaved.
+func TestReaderFromWriterConcurrentWrites(t *testing.T) {
+	req := require.New(t)
+	assr := assert.New(t)
+
+	chunks := [][]byte{bytes.Repeat([]byte("a"), 40), bytes.Repeat([]byte("b"), 40)}
+	for _, size := range []int{0, 4} {
+		erf := &ExampleReaderFrom{}
+		rfw := gio.NewBufferedReaderFromWriter(erf, size)
+		var wg sync.WaitGroup
+		for _, chunk := range chunks {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				n, err := rfw.Write(chunk)
+				assr.NoError(err)
+				assr.Equal(len(chunk), n)
+			}()
+		}
+		wg.Wait()
+		req.NoError(rfw.Close())
+		s := string(erf.data)
+		req.True(s == string(chunks[0])+string(chunks[1]) || s == string(chunks[1])+string(chunks[0]),
+			"size=%d: %s", size, s)
+	}
+
+	// Only the bytes actually read are counted as written.
+	rfw := gio.NewReaderFromWriter(readerFromFunc(func(r io.Reader) (int64, error) {
+		return io.CopyN(io.Discard, r, 5)
+	}))
+	var wg sync.WaitGroup
+	var total atomic.Int64
+	for _, chunk := range chunks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n, err := rfw.Write(chunk[:10])
+			assr.ErrorIs(err, io.ErrClosedPipe)
+			assr.True(n >= 0 && n <= 10, n)
+			total.Add(int64(n))
+		}()
+	}
+	wg.Wait()
+	req.Equal(int64(5), total.Load())
+	req.NoError(rfw.Close())
 }
@@ -3,15 +3,19 @@ package io
 import (
 	"errors"
 	"io"
+	"sync"
 )
 
 // ReaderFromWriteToReadWriteCloser is a type that wraps an io.ReaderFrom and a io.WriterTo, and
-// implements io.ReadWriteCloser, ReadFromWriteCloser and WriteToReadCloser using io.Pipe.
-// Due to the asynchronous nature of io.Pipe, Write() will only be guaranteed to be visible after
-// a call to Sync() or Close().
+// implements io.ReadWriteCloser, ReadFromWriteCloser and WriteToReadCloser using pipes.
+// Due to the asynchronous nature of the pipes, Write() will only be guaranteed to be visible after
+// a call to Flush(), Sync() or Close().
 type ReaderFromWriteToReadWriteCloser struct {
 	*ReaderFromWriter
 	*WriterToReader
+
+	doneOnce sync.Once
+	done     chan struct{}
 }
 
 var _ ReadFromWriteToReadWriteCloser = (*ReaderFromWriteToReadWriteCloser)(nil)
@@ -27,9 +31,30 @@ func NewReadFromWriteToReadWriterCloser(
 	}
 }
 
-// Close closes the pipe writer and waits for the readFromPipe goroutine to finish.
+// Close closes both the ReaderFromWriter and the WriterToReader, and returns their errors joined.
+// It waits for the wrapped ReadFrom() and WriteTo() to return, see WriterToReader.Close().
 func (rwc *ReaderFromWriteToReadWriteCloser) Close() error {
 	err1 := rwc.ReaderFromWriter.Close()
 	err2 := rwc.WriterToReader.Close()
 	return errors.Join(err1, err2)
 }
+
+// Done returns a channel that's closed when both the readFromPipe and writeToPipe goroutines
+// finish.
+func (rwc *ReaderFromWriteToReadWriteCloser) Done() <-chan struct{} {
+	rwc.doneOnce.Do(func() {
+		rwc.done = make(chan struct{})
+		go func() {
+			<-rwc.ReaderFromWriter.Done()
+			<-rwc.WriterToReader.Done()
+			close(rwc.done)
+		}()
+	})
+	return rwc.done
+}
+
+// Err returns the errors returned by the wrapped ReadFrom() and WriteTo() joined, see
+// ReaderFromWriter.Err() and WriterToReader.Err().
+func (rwc *ReaderFromWriteToReadWriteCloser) Err() error {
+	return errors.Join(rwc.ReaderFromWriter.Err(), rwc.WriterToReader.Err())
+}
@@ -4,6 +4,8 @@ import (
 	"io"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	gio "github.com/daotl/guts/io"
 )
 
@@ -23,3 +25,27 @@ func TestReaderFromWriteToReadWriteCloser(t *testing.T) {
 		},
 	)
 }
+
+// TestReaderFromWriteToReadWriteCloserDone tests Done and Err of both goroutines.
+func TestReaderFromWriteToReadWriteCloserDone(t *testing.T) {
+	req := require.New(t)
+
+	erf := &ExampleReaderFrom{}
+	rwc := gio.NewReadFromWriteToReadWriterCloser(erf, writerToFunc(func(w io.Writer) (int64, error) {
+		return 0, io.ErrUnexpectedEOF
+	}))
+	done := rwc.Done()
+	req.Equal(done, rwc.Done())
+	select {
+	case <-done:
+		req.Fail("Done before ReadFrom returns")
+	default:
+	}
+
+	_, err := rwc.Write(TestBin)
+	req.NoError(err)
+	req.ErrorIs(rwc.Close(), io.ErrUnexpectedEOF)
+	<-done
+	req.ErrorIs(rwc.Err(), io.ErrUnexpectedEOF)
+	req.Equal(TestStr, string(erf.data))
+}
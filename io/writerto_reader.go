@@ -1,32 +1,117 @@
 package io
 
 import (
+	"context"
+	"errors"
 	"io"
+	"sync/atomic"
 )
 
 // WriterToReader is a type that wraps an io.WriterTo implementation and implements WriterToReader using io.Pipe.
 type WriterToReader struct {
 	io.WriterTo
 	*io.PipeReader
-	pipeW *io.PipeWriter
+	ctx    context.Context
+	pipeW  *io.PipeWriter
+	done   chan struct{}
+	closed atomic.Bool
+	read   atomic.Int64
+	n      int64
+	err    error
 }
 
 var _ WriteToReadCloser = (*WriterToReader)(nil)
 
 // NewWriterToReader creates a new WriterToReader.
 func NewWriterToReader(writerTo io.WriterTo) *WriterToReader {
+	return NewWriterToReaderWithContext(context.Background(), writerTo)
+}
+
+// NewWriterToReaderWithContext creates a new WriterToReader which aborts the pipe with ctx.Err()
+// when `ctx` is done, so that both Read() and the writes of the wrapped WriteTo() fail with
+// ctx.Err() instead of blocking forever.
+func NewWriterToReaderWithContext(ctx context.Context, writerTo io.WriterTo) *WriterToReader {
 	pipeR, pipeW := io.Pipe()
 	wtr := &WriterToReader{
 		WriterTo:   writerTo,
 		PipeReader: pipeR,
+		ctx:        ctx,
 		pipeW:      pipeW,
+		done:       make(chan struct{}),
 	}
 	go wtr.writeToPipe()
+	if ctx.Done() != nil {
+		go abortPipeOnDone(ctx, wtr.done, pipeR.CloseWithError)
+	}
 	return wtr
 }
 
 // writeToPipe writes data from the writerTo to the pipe.
 func (wtr *WriterToReader) writeToPipe() {
-	_, err := wtr.WriteTo(wtr.pipeW)
-	wtr.pipeW.CloseWithError(err)
+	defer close(wtr.done)
+	wtr.n, wtr.err = wtr.WriteTo(wtr.pipeW)
+	// Writing to the pipe closed by Close() isn't a failure of WriteTo.
+	if errors.Is(wtr.err, io.ErrClosedPipe) && wtr.closed.Load() {
+		wtr.err = nil
+	}
+	wtr.pipeW.CloseWithError(wtr.err)
+}
+
+// Read reads from the pipe, returns ctx.Err() if the pipe is aborted.
+func (wtr *WriterToReader) Read(p []byte) (int, error) {
+	n, err := wtr.PipeReader.Read(p)
+	wtr.read.Add(int64(n))
+	return n, pipeError(wtr.ctx, err)
+}
+
+// N returns the number of bytes read from the WriterToReader so far.
+func (wtr *WriterToReader) N() int64 {
+	return wtr.read.Load()
+}
+
+// Done returns a channel that's closed when the writeToPipe goroutine finishes.
+func (wtr *WriterToReader) Done() <-chan struct{} {
+	return wtr.done
+}
+
+// Err returns the error returned by the wrapped WriteTo() like Close(), or nil if it hasn't
+// returned yet.
+func (wtr *WriterToReader) Err() error {
+	select {
+	case <-wtr.done:
+		return wtr.err
+	default:
+		return nil
+	}
+}
+
+// Wait waits for the writeToPipe goroutine to finish and returns the number of bytes written and
+// the error returned by the wrapped WriteTo(). Wait blocks as long as WriteTo() is blocked writing
+// to the pipe, so it should be called after reading to EOF, or use Close() instead.
+func (wtr *WriterToReader) Wait() (int64, error) {
+	<-wtr.done
+	return wtr.n, wtr.err
+}
+
+// Close closes the pipe reader, which fails the pending and following writes of the wrapped
+// WriteTo() with io.ErrClosedPipe, then waits for it to return. It returns the error returned by
+// WriteTo() unless it's caused by closing the pipe.
+//
+// Closing the pipe can't interrupt WriteTo() blocked on anything else, e.g. reading from a socket,
+// in which case Close blocks until it returns. Use NewWriterToReaderWithContext() and cancel the
+// context to stop waiting, Close then returns ctx.Err() while WriteTo() may still be running.
+func (wtr *WriterToReader) Close() error {
+	wtr.closed.Store(true)
+	_ = wtr.PipeReader.Close()
+	select {
+	case <-wtr.done:
+		return wtr.err
+	case <-wtr.ctx.Done():
+		select {
+		case <-wtr.done:
+			return wtr.err
+		default:
+			return wtr.ctx.Err()
+		}
+	}
 }
@@ -2,8 +2,10 @@ package io_test
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"testing"
+	"time"
 
 	gio "github.com/daotl/guts/io"
 	"github.com/stretchr/testify/assert"
@@ -30,6 +32,9 @@ func testWriterToReader(
 		req.NoError(err)
 		assr.Equal(len(TestStr), n)
 		assr.Equal(TestStr, string(buf))
+
+		// Close the WriterToReader to release the resources.
+		req.NoError(wtr.Close())
 	})
 
 	// Tests the WriterToReader implementation with multiple reads.
@@ -53,6 +58,7 @@ func testWriterToReader(
 		}
 
 		assr.Equal(TestStr, result.String())
+		req.NoError(wtr.Close())
 	})
 
 	// Tests the WriterToReader implementation with an empty WriterTo.
@@ -69,6 +75,7 @@ func testWriterToReader(
 		n2, err2 := wtr.Read(buf)
 		assr.Equal(io.EOF, err2)
 		assr.Equal(0, n2)
+		req.NoError(wtr.Close())
 	})
 }
 
@@ -79,3 +86,120 @@ func TestWriterToReader(t *testing.T) {
 		func(wt io.WriterTo) gio.WriteToReadCloser { return gio.NewWriterToReader(wt) },
 	)
 }
+
+// infiniteWriterTo writes TestBin repeatedly until an error occurs.
+type infiniteWriterTo struct{}
+
+func (infiniteWriterTo) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for {
+		n, err := w.Write(TestBin)
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+// TestWriterToReaderWithContext tests that canceling the context aborts the pipe.
+func TestWriterToReaderWithContext(t *testing.T) {
+	req := require.New(t)
+
+	testWriterToReader(
+		t,
+		func(wt io.WriterTo) gio.WriteToReadCloser {
+			return gio.NewWriterToReaderWithContext(context.Background(), wt)
+		},
+	)
+
+	// A WriteTo blocked by no reading stops when the context is canceled.
+	ctx, cancel := context.WithCancel(context.Background())
+	wtr := gio.NewWriterToReaderWithContext(ctx, infiniteWriterTo{})
+	buf := make([]byte, len(TestBin))
+	_, err := io.ReadFull(wtr, buf)
+	req.NoError(err)
+	req.NoError(wtr.Err())
+
+	cancel()
+	<-wtr.Done()
+	req.ErrorIs(wtr.Err(), context.Canceled)
+	_, err = wtr.Read(buf)
+	req.ErrorIs(err, context.Canceled)
+	req.ErrorIs(wtr.Close(), context.Canceled)
+}
+
+// writerToFunc is an adapter to allow the use of ordinary functions as io.WriterTo.
+type writerToFunc func(w io.Writer) (int64, error)
+
+func (f writerToFunc) WriteTo(w io.Writer) (int64, error) {
+	return f(w)
+}
+
+// TestWriterToReaderClose tests that Close and Wait report the result of WriteTo.
+func TestWriterToReaderClose(t *testing.T) {
+	req := require.New(t)
+
+	// Wait after reading to EOF.
+	wtr := gio.NewWriterToReader(&ExampleWriterTo{data: TestBin})
+	b, err := io.ReadAll(wtr)
+	req.NoError(err)
+	req.Equal(TestStr, string(b))
+	n, err := wtr.Wait()
+	req.NoError(err)
+	req.Equal(int64(len(TestBin)), n)
+	req.Equal(int64(len(TestBin)), wtr.N())
+	req.NoError(wtr.Close())
+
+	// Closing stops a WriteTo blocked by no reading, the resulting io.ErrClosedPipe is ignored.
+	wtr = gio.NewWriterToReader(infiniteWriterTo{})
+	buf := make([]byte, 2*len(TestBin))
+	_, err = io.ReadFull(wtr, buf)
+	req.NoError(err)
+	req.Equal(int64(len(buf)), wtr.N())
+	req.NoError(wtr.Close())
+	n, err = wtr.Wait()
+	req.NoError(err)
+	req.Equal(int64(len(buf)), n)
+	req.NoError(wtr.Err())
+
+	// Other errors of WriteTo are returned by Close.
+	wtr = gio.NewWriterToReader(writerToFunc(func(w io.Writer) (int64, error) {
+		n, _ := w.Write(TestBin)
+		return int64(n), io.ErrUnexpectedEOF
+	}))
+	_, err = io.ReadAll(wtr)
+	req.ErrorIs(err, io.ErrUnexpectedEOF)
+	req.ErrorIs(wtr.Close(), io.ErrUnexpectedEOF)
+	n, err = wtr.Wait()
+	req.ErrorIs(err, io.ErrUnexpectedEOF)
+	req.Equal(int64(len(TestBin)), n)
+}
+
+// TestWriterToReaderCloseBlocked tests that Close waits for a WriteTo blocked on something else
+// than the pipe until the context is canceled.
+func TestWriterToReaderCloseBlocked(t *testing.T) {
+	req := require.New(t)
+
+	release := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	wtr := gio.NewWriterToReaderWithContext(ctx, writerToFunc(func(w io.Writer) (int64, error) {
+		<-release
+		return 0, io.ErrUnexpectedEOF
+	}))
+
+	closed := make(chan error, 1)
+	go func() { closed <- wtr.Close() }()
+	select {
+	case <-closed:
+		req.Fail("Close returned before WriteTo")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel()
+	req.ErrorIs(<-closed, context.Canceled)
+
+	// WriteTo is still running, its result is reported by Wait.
+	close(release)
+	_, err := wtr.Wait()
+	req.ErrorIs(err, io.ErrUnexpectedEOF)
+}
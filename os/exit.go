@@ -0,0 +1,171 @@
+package os
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	gerror "github.com/daotl/guts/error"
+	gsync "github.com/daotl/guts/sync"
+)
+
+// ErrUsage should be wrapped by errors caused by invalid command-line usage, ExitWithError exits
+// with code 2 for them.
+var ErrUsage = errors.New("usage error")
+
+// ExitCoder is implemented by errors carrying their own exit code, e.g. *exec.ExitError.
+type ExitCoder interface {
+	ExitCode() int
+}
+
+type exitCodeMapping struct {
+	match func(err error) bool
+	code  int
+}
+
+var exitState = struct {
+	mu       gsync.Mutex
+	mappings []exitCodeMapping
+	hooks    []func()
+	report   io.Writer
+}{
+	mappings: []exitCodeMapping{{isError(ErrUsage), 2}},
+}
+
+// RegisterExitCode maps errors matching `target` by errors.Is to exit code `code`, which also
+// works with wrapped errors and *error.Error with the same code. Mappings registered later take
+// precedence, so the default code 2 of ErrUsage can be overridden.
+func RegisterExitCode(target error, code int) {
+	registerExitCode(isError(target), code)
+}
+
+// RegisterExitCodeType maps errors of type `T` in the chain by errors.As to exit code `code`, e.g.
+// RegisterExitCodeType[*fs.PathError](66). Mappings registered later take precedence.
+func RegisterExitCodeType[T error](code int) {
+	registerExitCode(func(err error) bool {
+		var target T
+		return errors.As(err, &target)
+	}, code)
+}
+
+// RegisterExitCodeFunc maps errors for which `match` returns true to exit code `code`. Mappings
+// registered later take precedence.
+func RegisterExitCodeFunc(match func(err error) bool, code int) {
+	registerExitCode(match, code)
+}
+
+func registerExitCode(match func(err error) bool, code int) {
+	exitState.mu.Lock()
+	defer exitState.mu.Unlock()
+	exitState.mappings = append(exitState.mappings, exitCodeMapping{match, code})
+}
+
+func isError(target error) func(err error) bool {
+	return func(err error) bool { return errors.Is(err, target) }
+}
+
+// OnExit registers a cleanup hook to run by Exit and ExitWithError before exiting, the hooks run in
+// the reverse order of registration, like deferred calls.
+func OnExit(fn func()) {
+	exitState.mu.Lock()
+	defer exitState.mu.Unlock()
+	exitState.hooks = append(exitState.hooks, fn)
+}
+
+// SetExitReport sets the writer ExitWithError emits a JSON error report to, in addition to the
+// message written to stderr. The report is disabled if `w` is nil, which is the default.
+func SetExitReport(w io.Writer) {
+	exitState.mu.Lock()
+	defer exitState.mu.Unlock()
+	exitState.report = w
+}
+
+// ExitCode returns the exit code for `err`: 0 for nil, the code of the first ExitCoder in the
+// chain, the code of the latest matching mapping registered by RegisterExitCode,
+// RegisterExitCodeType or RegisterExitCodeFunc, or 1 otherwise. Codes <= 0 are mapped to 1 for
+// non-nil errors, e.g. -1 of *exec.ExitError for a process killed by a signal, so that failures
+// never exit successfully.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if code := exitCode(err); code > 0 {
+		return code
+	}
+	return 1
+}
+
+func exitCode(err error) int {
+	var ec ExitCoder
+	if errors.As(err, &ec) {
+		return ec.ExitCode()
+	}
+
+	// Call the matchers without holding the lock, they may call ExitCode or register mappings.
+	exitState.mu.Lock()
+	mappings := exitState.mappings
+	exitState.mu.Unlock()
+	for i := len(mappings) - 1; i >= 0; i-- {
+		if m := mappings[i]; m.match(err) {
+			return m.code
+		}
+	}
+	return 1
+}
+
+// exitReport is the JSON error report emitted by ExitWithError.
+type exitReport struct {
+	ExitCode int             `json:"exit_code"`
+	Error    string          `json:"error"`
+	Code     string          `json:"code,omitempty"`
+	Detail   json.RawMessage `json:"detail,omitempty"`
+}
+
+// ExitWithError prints `err` to stderr, emits the JSON error report if set by SetExitReport, runs
+// the hooks registered by OnExit, then exits with ExitCode(err). Nothing is printed if `err` is
+// nil.
+func ExitWithError(err error) {
+	code := ExitCode(err)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+
+		exitState.mu.Lock()
+		w := exitState.report
+		exitState.mu.Unlock()
+		if w != nil {
+			writeExitReport(w, err, code)
+		}
+	}
+	exit(code)
+}
+
+func writeExitReport(w io.Writer, err error, code int) {
+	report := exitReport{ExitCode: code, Error: err.Error(), Code: gerror.CodeOf(err)}
+	if report.Code == "" {
+		report.Code, _ = gerror.RegisteredCode(err)
+	}
+	if detail, e := gerror.EncodeJSON(err); e == nil {
+		report.Detail = detail
+	}
+	if b, e := json.Marshal(report); e == nil {
+		_, _ = w.Write(append(b, '\n'))
+	}
+}
+
+// exit runs the hooks registered by OnExit then exits with `code`. A panicking hook doesn't stop
+// the others or exiting.
+func exit(code int) {
+	exitState.mu.Lock()
+	hooks := exitState.hooks
+	exitState.hooks = nil
+	exitState.mu.Unlock()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := gerror.CatchPanic(func() error { hooks[i](); return nil }); err != nil {
+			fmt.Fprintf(os.Stderr, "Exit hook failed: %v\n", err)
+		}
+	}
+	os.Exit(code)
+}
@@ -0,0 +1,122 @@
+package os_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gerror "github.com/daotl/guts/error"
+	gos "github.com/daotl/guts/os"
+)
+
+var (
+	errTestConfig   = errors.New("invalid config")
+	errTestNotFound = gerror.New("os_test.not_found", "not found")
+	errTestOverride = errors.New("overridden")
+)
+
+func init() {
+	gos.RegisterExitCode(errTestConfig, 78)
+	gos.RegisterExitCode(errTestNotFound, 4)
+	gos.RegisterExitCodeType[*fs.PathError](66)
+	gos.RegisterExitCodeFunc(func(err error) bool {
+		return strings.HasPrefix(err.Error(), "temporary:")
+	}, 75)
+	// Matchers may call ExitCode.
+	gos.RegisterExitCodeFunc(func(err error) bool {
+		return strings.HasPrefix(err.Error(), "reload:") && gos.ExitCode(errors.Unwrap(err)) == 78
+	}, 79)
+	gos.RegisterExitCode(errTestOverride, 10)
+	gos.RegisterExitCode(errTestOverride, 11)
+}
+
+type exitCodeError struct {
+	code int
+}
+
+func (e exitCodeError) Error() string {
+	return fmt.Sprintf("exit code %d", e.code)
+}
+
+func (e exitCodeError) ExitCode() int {
+	return e.code
+}
+
+func TestExitCode(t *testing.T) {
+	assr := assert.New(t)
+
+	assr.Equal(0, gos.ExitCode(nil))
+	assr.Equal(1, gos.ExitCode(io.EOF))
+	assr.Equal(2, gos.ExitCode(fmt.Errorf("unknown flag -x: %w", gos.ErrUsage)))
+	assr.Equal(78, gos.ExitCode(fmt.Errorf("load: %w", errTestConfig)))
+	// *error.Error matches by code.
+	assr.Equal(4, gos.ExitCode(errTestNotFound.With("id", 1)))
+	assr.Equal(5, gos.ExitCode(fmt.Errorf("child: %w", exitCodeError{5})))
+	// Errors of a registered type.
+	_, err := os.Open("/nonexistent/guts")
+	assr.Equal(66, gos.ExitCode(fmt.Errorf("load: %w", err)))
+	assr.Equal(75, gos.ExitCode(errors.New("temporary: try again")))
+	assr.Equal(79, gos.ExitCode(fmt.Errorf("reload: %w", errTestConfig)))
+	// Non-positive codes of failures are mapped to 1.
+	assr.Equal(1, gos.ExitCode(exitCodeError{0}))
+	assr.Equal(1, gos.ExitCode(exitCodeError{-1}))
+	// Later registrations take precedence.
+	assr.Equal(11, gos.ExitCode(errTestOverride))
+	// ExitCoder takes precedence over the registered mappings.
+	assr.Equal(6, gos.ExitCode(errors.Join(errTestConfig, exitCodeError{6})))
+}
+
+func TestExitWithError(t *testing.T) {
+	if os.Getenv("GUTS_EXIT_WITH_ERROR_TEST") == "1" {
+		gos.SetExitReport(os.Stdout)
+		gos.OnExit(func() { fmt.Println("hook 1") })
+		gos.OnExit(func() { panic("hook 2 failed") })
+		gos.OnExit(func() { fmt.Println("hook 3") })
+		gos.ExitWithError(fmt.Errorf("unknown flag -x: %w", gos.ErrUsage))
+		return
+	}
+
+	req := require.New(t)
+	cmd, stdout, stderr := newTestProgram(t, "GUTS_EXIT_WITH_ERROR_TEST")
+	err := cmd.Run()
+	var ee *exec.ExitError
+	req.ErrorAs(err, &ee)
+	req.Equal(2, ee.ExitCode())
+
+	req.True(strings.HasPrefix(stderr.String(), "Error: unknown flag -x: usage error\n"), stderr.String())
+	req.Contains(stderr.String(), "Exit hook failed: panic: hook 2 failed")
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	req.Len(lines, 3)
+	var report map[string]any
+	req.NoError(json.Unmarshal([]byte(lines[0]), &report))
+	req.Equal(float64(2), report["exit_code"])
+	req.Equal("unknown flag -x: usage error", report["error"])
+	req.NotNil(report["detail"])
+	// The hooks run in the reverse order of registration.
+	req.Equal([]string{"hook 3", "hook 1"}, lines[1:])
+}
+
+func TestExit(t *testing.T) {
+	if os.Getenv("GUTS_EXIT_TEST") == "1" {
+		gos.Exit("100% done")
+		return
+	}
+
+	req := require.New(t)
+	cmd, stdout, _ := newTestProgram(t, "GUTS_EXIT_TEST")
+	err := cmd.Run()
+	var ee *exec.ExitError
+	req.ErrorAs(err, &ee)
+	req.Equal(1, ee.ExitCode())
+	req.Equal("100% done\n", stdout.String())
+}
@@ -36,10 +36,12 @@ func TrapSignal(logger logger, cb func()) {
 	}()
 }
 
-// Exit prints string `s` then `os.Exit(1)`.
+// Exit prints string `s`, runs the hooks registered by OnExit, then `os.Exit(1)`.
+//
+// Use ExitWithError to print to stderr and exit with a code depending on the error.
 func Exit(s string) {
-	fmt.Printf(s + "\n")
-	os.Exit(1)
+	fmt.Println(s)
+	exit(1)
 }
 
 // EnsureDir ensures the given directory exists, creating it if necessary.
@@ -85,6 +85,18 @@ func Bytes(n int) []byte {
 	return bs
 }
 
+// Intn returns a random int in [0, n) from math/rand's global default Source, panics if n <= 0.
+func Intn(n int) int {
+	// nolint:gosec // G404: Use of weak random number generator
+	return mrand.Intn(n)
+}
+
+// Float64 returns a random float64 in [0.0, 1.0) from math/rand's global default Source.
+func Float64() float64 {
+	// nolint:gosec // G404: Use of weak random number generator
+	return mrand.Float64()
+}
+
 func crandSeed() int64 {
 	var seed int64
 	err := binary.Read(crand.Reader, binary.BigEndian, &seed)
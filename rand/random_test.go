@@ -24,6 +24,21 @@ func TestRandBytes(t *testing.T) {
 	assert.Equal(t, l, len(b))
 }
 
+func TestRandIntn(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		v := Intn(10)
+		assert.True(t, v >= 0 && v < 10)
+	}
+	assert.Panics(t, func() { Intn(0) })
+}
+
+func TestRandFloat64(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		v := Float64()
+		assert.True(t, v >= 0 && v < 1)
+	}
+}
+
 func BenchmarkRandBytes10B(b *testing.B) {
 	benchmarkRandBytes(b, 10)
 }
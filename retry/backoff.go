@@ -0,0 +1,71 @@
+package retry
+
+import (
+	"math"
+	"time"
+
+	"github.com/daotl/guts/rand"
+)
+
+// Backoff determines the delay before each retry.
+type Backoff interface {
+	// Delay returns the delay before retry `n`, starting from 1 for the retry after the first
+	// attempt failed.
+	Delay(n int) time.Duration
+}
+
+// BackoffFunc is a function implementing Backoff.
+type BackoffFunc func(n int) time.Duration
+
+// Delay implements Backoff.
+func (f BackoffFunc) Delay(n int) time.Duration {
+	return f(n)
+}
+
+// Constant returns a Backoff that always delays `d`.
+func Constant(d time.Duration) Backoff {
+	return BackoffFunc(func(int) time.Duration { return d })
+}
+
+// Default values of Exponential.
+const (
+	DefaultInitial    = 100 * time.Millisecond
+	DefaultMax        = 10 * time.Second
+	DefaultMultiplier = 2.0
+)
+
+// Exponential is a Backoff with delays growing exponentially, Initial * Multiplier^(n-1) capped at
+// Max, each randomized by up to ±Jitter fraction of itself using the `rand` package.
+//
+// The zero value uses DefaultInitial, DefaultMax and DefaultMultiplier without jitter.
+type Exponential struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+	// Jitter is in [0, 1], e.g. 0.2 randomizes a delay of 1s into [0.8s, 1.2s].
+	Jitter float64
+}
+
+// Delay implements Backoff.
+func (e Exponential) Delay(n int) time.Duration {
+	initial, maxDelay, mult := e.Initial, e.Max, e.Multiplier
+	if initial <= 0 {
+		initial = DefaultInitial
+	}
+	if maxDelay <= 0 {
+		maxDelay = DefaultMax
+	}
+	if mult < 1 {
+		mult = DefaultMultiplier
+	}
+
+	d := float64(initial) * math.Pow(mult, float64(max(n, 1)-1))
+	if d > float64(maxDelay) {
+		d = float64(maxDelay)
+	}
+	if e.Jitter > 0 {
+		jitter := min(e.Jitter, 1)
+		d += d * jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(d)
+}
@@ -0,0 +1,109 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/daotl/go-log/v2"
+
+	gerror "github.com/daotl/guts/error"
+)
+
+// Policy configures how Do retries.
+//
+// The zero value retries all errors not marked by error.Permanent without limit until the context
+// is done, using the zero value of Exponential as Backoff.
+type Policy struct {
+	// MaxAttempts is the maximum number of attempts including the first one, unlimited if <= 0.
+	MaxAttempts int
+	// Backoff determines the delay before each retry, the zero value of Exponential if nil.
+	Backoff Backoff
+	// AttemptTimeout is the timeout of the context passed to each attempt, no timeout if <= 0.
+	AttemptTimeout time.Duration
+	// Retryable reports whether an error not marked by error.Permanent should be retried, all of
+	// them are retried if nil.
+	Retryable func(err error) bool
+	// OnRetry is called after attempt `attempt` failed with `err` before waiting `delay` for the
+	// next attempt.
+	OnRetry func(attempt int, err error, delay time.Duration)
+	// Logger logs each failed attempt, nothing is logged if nil.
+	Logger log.StandardLogger
+}
+
+// Do calls `fn` until it succeeds, fails with an error not to be retried, the attempts are
+// exhausted or `ctx` is done, waiting between attempts according to `p`.
+//
+// It returns nil on success, the error of `fn` as is if it's not to be retried, or otherwise an
+// error wrapping the last error of `fn`, and also ctx.Err() if `ctx` is done.
+func Do(ctx context.Context, p Policy, fn func(ctx context.Context) error) error {
+	_, err := DoValue(ctx, p, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// DoValue is the same as Do except that `fn` also returns a value, which is returned on success.
+func DoValue[T any](ctx context.Context, p Policy, fn func(ctx context.Context) (T, error)) (T,
+	error) {
+	var zero T
+	backoff := p.Backoff
+	if backoff == nil {
+		backoff = Exponential{}
+	}
+	logger := p.Logger
+	if logger == nil {
+		logger = log.NopLogger()
+	}
+
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		val, err := callAttempt(ctx, p.AttemptTimeout, fn)
+		if err == nil {
+			return val, nil
+		}
+
+		if gerror.IsPermanent(err) || (p.Retryable != nil && !p.Retryable(err)) {
+			logger.Warnf("Attempt %d failed with a non-retryable error: %v", attempt, err)
+			return zero, err
+		}
+		if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+			logger.Errorf("Attempt %d failed, giving up: %v", attempt, err)
+			return zero, fmt.Errorf("retry: giving up after %d attempts: %w", attempt, err)
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return zero, fmt.Errorf("retry: %w after %d attempts: %w", ctxErr, attempt, err)
+		}
+
+		delay := backoff.Delay(attempt)
+		logger.Warnf("Attempt %d failed, retrying in %s: %v", attempt, delay, err)
+		if p.OnRetry != nil {
+			p.OnRetry(attempt, err, delay)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return zero, fmt.Errorf("retry: %w after %d attempts: %w", ctx.Err(), attempt, err)
+		case <-timer.C:
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return zero, fmt.Errorf("retry: %w after %d attempts: %w", ctxErr, attempt, err)
+			}
+		}
+	}
+}
+
+// callAttempt calls `fn` with a context with `timeout` if it's positive.
+func callAttempt[T any](ctx context.Context, timeout time.Duration,
+	fn func(ctx context.Context) (T, error)) (T, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return fn(ctx)
+}
@@ -0,0 +1,166 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	gerror "github.com/daotl/guts/error"
+	. "github.com/daotl/guts/retry"
+)
+
+var fastPolicy = Policy{Backoff: Constant(time.Millisecond)}
+
+func TestExponential(t *testing.T) {
+	assr := assert.New(t)
+
+	e := Exponential{}
+	assr.Equal(DefaultInitial, e.Delay(1))
+	assr.Equal(2*DefaultInitial, e.Delay(2))
+	assr.Equal(4*DefaultInitial, e.Delay(3))
+	assr.Equal(DefaultMax, e.Delay(100))
+
+	e = Exponential{Initial: time.Second, Max: time.Minute, Multiplier: 3, Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		d := e.Delay(2)
+		assr.True(d >= 1500*time.Millisecond && d <= 4500*time.Millisecond, d)
+	}
+
+	assr.Equal(time.Second, Constant(time.Second).Delay(10))
+}
+
+func TestDo(t *testing.T) {
+	req := require.New(t)
+
+	attempts := 0
+	err := Do(context.Background(), fastPolicy, func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return io.ErrUnexpectedEOF
+		}
+		return nil
+	})
+	req.NoError(err)
+	req.Equal(3, attempts)
+
+	val, err := DoValue(context.Background(), fastPolicy, func(ctx context.Context) (int, error) {
+		return 42, nil
+	})
+	req.NoError(err)
+	req.Equal(42, val)
+}
+
+func TestDoGiveUp(t *testing.T) {
+	req := require.New(t)
+
+	// Exhausted attempts.
+	attempts := 0
+	p := fastPolicy
+	p.MaxAttempts = 3
+	err := Do(context.Background(), p, func(ctx context.Context) error {
+		attempts++
+		return io.ErrUnexpectedEOF
+	})
+	req.ErrorIs(err, io.ErrUnexpectedEOF)
+	req.Equal("retry: giving up after 3 attempts: unexpected EOF", err.Error())
+	req.Equal(3, attempts)
+
+	// Permanent errors are returned as is.
+	attempts = 0
+	permanent := gerror.Permanent(io.EOF)
+	err = Do(context.Background(), fastPolicy, func(ctx context.Context) error {
+		attempts++
+		return permanent
+	})
+	req.Equal(permanent, err)
+	req.ErrorIs(err, io.EOF)
+	req.Equal(1, attempts)
+
+	// Classified as non-retryable.
+	attempts = 0
+	p = fastPolicy
+	p.Retryable = func(err error) bool { return !errors.Is(err, io.EOF) }
+	err = Do(context.Background(), p, func(ctx context.Context) error {
+		attempts++
+		if attempts == 1 {
+			return io.ErrUnexpectedEOF
+		}
+		return io.EOF
+	})
+	req.Equal(io.EOF, err)
+	req.Equal(2, attempts)
+}
+
+func TestDoContext(t *testing.T) {
+	req := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	called := false
+	err := Do(ctx, fastPolicy, func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	req.ErrorIs(err, context.Canceled)
+	req.False(called)
+
+	// Canceled while waiting for the next attempt.
+	ctx, cancel = context.WithCancel(context.Background())
+	p := Policy{
+		Backoff: Constant(time.Hour),
+		OnRetry: func(attempt int, err error, delay time.Duration) { cancel() },
+	}
+	err = Do(ctx, p, func(ctx context.Context) error { return io.ErrUnexpectedEOF })
+	req.ErrorIs(err, context.Canceled)
+	req.ErrorIs(err, io.ErrUnexpectedEOF)
+
+	// Per-attempt timeouts.
+	attempts := 0
+	p = fastPolicy
+	p.AttemptTimeout = 10 * time.Millisecond
+	err = Do(context.Background(), p, func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	})
+	req.NoError(err)
+	req.Equal(3, attempts)
+}
+
+func TestDoHooks(t *testing.T) {
+	req := require.New(t)
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	var retries []int
+	p := Policy{
+		MaxAttempts: 3,
+		Backoff:     Constant(time.Millisecond),
+		OnRetry: func(attempt int, err error, delay time.Duration) {
+			req.ErrorIs(err, io.ErrUnexpectedEOF)
+			req.Equal(time.Millisecond, delay)
+			retries = append(retries, attempt)
+		},
+		Logger: zap.New(core).Sugar(),
+	}
+	err := Do(context.Background(), p, func(ctx context.Context) error { return io.ErrUnexpectedEOF })
+	req.Error(err)
+	req.Equal([]int{1, 2}, retries)
+
+	entries := logs.All()
+	req.Len(entries, 3)
+	req.Equal("Attempt 1 failed, retrying in 1ms: unexpected EOF", entries[0].Message)
+	req.Equal(zapcore.WarnLevel, entries[0].Level)
+	req.Equal("Attempt 3 failed, giving up: unexpected EOF", entries[2].Message)
+	req.Equal(zapcore.ErrorLevel, entries[2].Level)
+}
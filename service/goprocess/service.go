@@ -6,6 +6,7 @@ import (
 	"github.com/jbenet/goprocess"
 
 	"github.com/daotl/go-log/v2"
+	gerror "github.com/daotl/guts/error"
 )
 
 var (
@@ -13,6 +14,11 @@ var (
 	ErrNotStarted           = errors.New("service not started")
 )
 
+func init() {
+	gerror.Register("goprocesssrv.run_fn_must_be_specified", ErrRunFnMustBeSpecified)
+	gerror.Register("goprocesssrv.not_started", ErrNotStarted)
+}
+
 // Service is a goprocess-based service interface
 type Service interface {
 	// Start the service, optionally with a parent service.
@@ -8,6 +8,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/daotl/go-log/v2"
+	gerror "github.com/daotl/guts/error"
 	gsync "github.com/daotl/guts/sync"
 )
 
@@ -27,6 +28,13 @@ var (
 	ErrNotRunning           = errors.New("not running")
 )
 
+func init() {
+	gerror.Register("suturesrv.stopped_before_ready", ErrStoppedBeforeReady)
+	gerror.Register("suturesrv.run_fn_must_be_specified", ErrRunFnMustBeSpecified)
+	gerror.Register("suturesrv.already_running", ErrAlreadyRunning)
+	gerror.Register("suturesrv.not_running", ErrNotRunning)
+}
+
 // Status of a Service
 type Status uint8
 